@@ -0,0 +1,106 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/component/file_cache"
+
+	"github.com/spf13/cobra"
+)
+
+type cacheOptions struct {
+	ConfigFile string
+	PinFile    string
+	List       bool
+	Recursive  bool
+	FileCache  bool
+}
+
+var cacheOpts cacheOptions
+
+// Section defining all the commands that manage the local file cache of a mount
+var cacheCmd = &cobra.Command{
+	Use:               "cache",
+	Short:             "Manage the local file cache of a mount",
+	Long:              "Manage the local file cache of a mount",
+	SuggestFor:        []string{"cach", "cahce"},
+	Example:           "blobfuse2 cache pin datasets/** --config-file=config.yaml",
+	FlagErrorHandling: cobra.ExitOnError,
+}
+
+// getFileCacheOptions : Load the given config file and read the file_cache section out of it
+func getFileCacheOptions(configFile string) (file_cache.FileCacheOptions, error) {
+	conf := file_cache.FileCacheOptions{}
+
+	options.ConfigFile = configFile
+	if options.ConfigFile == "" {
+		if _, err := os.Stat(common.DefaultConfigFilePath); err == nil {
+			options.ConfigFile = common.DefaultConfigFilePath
+		}
+	}
+
+	if options.ConfigFile == "" {
+		return conf, errors.New("config file not provided, check usage")
+	}
+
+	err := parseConfig()
+	if err != nil {
+		return conf, err
+	}
+
+	err = config.UnmarshalKey("file_cache", &conf)
+	if err != nil {
+		return conf, err
+	}
+
+	if conf.TmpPath == "" && conf.PinFile == "" {
+		return conf, errors.New("file_cache path not set in config file")
+	}
+
+	return conf, nil
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+
+	// Flags that needs to be accessible at all subcommand level shall be defined in persistentflags only
+	cacheCmd.PersistentFlags().StringVar(&cacheOpts.ConfigFile, "config-file", "",
+		"Configuration file of the mount. Default is config.yaml in current directory.")
+	_ = cacheCmd.MarkPersistentFlagFilename("config-file", "yaml")
+}
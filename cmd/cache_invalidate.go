@@ -0,0 +1,116 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+// Mounts are looked up through this, so tests can stand in for a real mount
+var listMountPoints = common.ListMountPoints
+
+var invalidateCmd = &cobra.Command{
+	Use:               "invalidate <path>",
+	Short:             "Drop cached attributes of a path in a running mount so they are fetched from storage again",
+	Long:              "Drop cached attributes and directory listings of a path in a running mount so they are fetched from storage again, useful after data was changed in the container by other means. Optionally purge cached files too, files not yet uploaded are kept.",
+	SuggestFor:        []string{"invalidat", "invalid"},
+	Example:           "blobfuse2 cache invalidate ~/mount_path/incoming --recursive\nblobfuse2 cache invalidate ~/mount_path/data.csv --file-cache",
+	Args:              cobra.ExactArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mountPath, name, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := admin.Send(mountPath, admin.Request{
+			Verb:      admin.VerbInvalidate,
+			Path:      name,
+			Recursive: cacheOpts.Recursive,
+			FileCache: cacheOpts.FileCache,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to invalidate %s [%s]", args[0], err.Error())
+		}
+
+		if resp.Message != "" {
+			fmt.Println(resp.Message)
+		}
+		return nil
+	},
+}
+
+// resolveMountPath : Mount holding the given local path and the path relative to the root of that mount
+func resolveMountPath(path string) (string, string, error) {
+	absPath, err := filepath.Abs(common.ExpandPath(path))
+	if err != nil {
+		return "", "", err
+	}
+
+	mounts, err := listMountPoints()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list mount points [%s]", err.Error())
+	}
+
+	// Mounts may be nested, so the innermost one holding the path wins
+	mountPath := ""
+	for _, mnt := range mounts {
+		mnt = filepath.Clean(mnt)
+		if (absPath == mnt || strings.HasPrefix(absPath, mnt+"/")) && len(mnt) > len(mountPath) {
+			mountPath = mnt
+		}
+	}
+
+	if mountPath == "" {
+		return "", "", fmt.Errorf("%s is not inside a blobfuse2 mount", path)
+	}
+
+	return mountPath, strings.TrimPrefix(strings.TrimPrefix(absPath, mountPath), "/"), nil
+}
+
+func init() {
+	cacheCmd.AddCommand(invalidateCmd)
+
+	invalidateCmd.Flags().BoolVar(&cacheOpts.Recursive, "recursive", false,
+		"Invalidate everything under the given directory as well")
+	invalidateCmd.Flags().BoolVar(&cacheOpts.FileCache, "file-cache", false,
+		"Purge the cached files too, not only their attributes")
+}
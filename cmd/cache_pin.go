@@ -0,0 +1,155 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/azure-storage-fuse/v2/component/file_cache"
+
+	"github.com/spf13/cobra"
+)
+
+var pinCmd = &cobra.Command{
+	Use:               "pin [glob...]",
+	Short:             "Keep files matching the given globs in local cache irrespective of cache timeout and usage",
+	Long:              "Keep files matching the given globs in local cache irrespective of cache timeout and usage. Globs are relative to the root of the mount and a running mount picks up the change within a second.",
+	SuggestFor:        []string{"pn", "pinn"},
+	Example:           "blobfuse2 cache pin datasets/** --config-file=config.yaml\nblobfuse2 cache pin --list --config-file=config.yaml",
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cacheOpts.List && len(args) == 0 {
+			return errors.New("globs to be pinned not provided, check usage")
+		}
+
+		pinFile, pins, err := loadPins()
+		if err != nil {
+			return err
+		}
+
+		if cacheOpts.List {
+			for _, pin := range pins {
+				fmt.Println(pin)
+			}
+			return nil
+		}
+
+		for _, glob := range args {
+			if !containsPin(pins, glob) {
+				pins = append(pins, glob)
+			}
+		}
+
+		err = file_cache.WritePinFile(pinFile, pins)
+		if err != nil {
+			return fmt.Errorf("failed to update pin file %s [%s]", pinFile, err.Error())
+		}
+
+		return nil
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:               "unpin <glob...>",
+	Short:             "Let files matching the given globs be evicted from local cache again",
+	Long:              "Let files matching the given globs be evicted from local cache again. Only globs added through pin can be removed, pins in config file stay.",
+	SuggestFor:        []string{"upin", "unpn"},
+	Example:           "blobfuse2 cache unpin datasets/** --config-file=config.yaml",
+	Args:              cobra.MinimumNArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pinFile, pins, err := loadPins()
+		if err != nil {
+			return err
+		}
+
+		remaining := make([]string, 0, len(pins))
+		for _, pin := range pins {
+			if !containsPin(args, pin) {
+				remaining = append(remaining, pin)
+			}
+		}
+
+		if len(remaining) == len(pins) {
+			return fmt.Errorf("none of the given globs is pinned")
+		}
+
+		err = file_cache.WritePinFile(pinFile, remaining)
+		if err != nil {
+			return fmt.Errorf("failed to update pin file %s [%s]", pinFile, err.Error())
+		}
+
+		return nil
+	},
+}
+
+// loadPins : Locate the pin file of the mount and read the globs in it
+func loadPins() (string, []string, error) {
+	pinFile := cacheOpts.PinFile
+	if pinFile == "" {
+		conf, err := getFileCacheOptions(cacheOpts.ConfigFile)
+		if err != nil {
+			return "", nil, err
+		}
+		pinFile = file_cache.PinFilePath(conf)
+	}
+
+	pins, err := file_cache.ReadPinFile(pinFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read pin file %s [%s]", pinFile, err.Error())
+	}
+
+	return pinFile, pins, nil
+}
+
+func containsPin(pins []string, glob string) bool {
+	for _, pin := range pins {
+		if pin == glob {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	cacheCmd.AddCommand(pinCmd)
+	cacheCmd.AddCommand(unpinCmd)
+
+	pinCmd.Flags().BoolVar(&cacheOpts.List, "list", false,
+		"List the globs pinned so far instead of adding new ones")
+
+	cacheCmd.PersistentFlags().StringVar(&cacheOpts.PinFile, "pin-file", "",
+		"Pin file of the mount, overrides the one derived from config file")
+}
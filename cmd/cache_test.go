@@ -0,0 +1,161 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type cacheCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *cacheCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *cacheCmdTestSuite) cleanupTest() {
+	cacheOpts = cacheOptions{}
+	listMountPoints = common.ListMountPoints
+	for _, cmd := range []*pflag.FlagSet{cacheCmd.PersistentFlags(), pinCmd.Flags(), unpinCmd.Flags(), invalidateCmd.Flags()} {
+		cmd.VisitAll(func(f *pflag.Flag) {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+}
+
+func TestCacheCommand(t *testing.T) {
+	suite.Run(t, new(cacheCmdTestSuite))
+}
+
+func (suite *cacheCmdTestSuite) TestPinNoGlob() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "cache", "pin")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "globs to be pinned not provided")
+}
+
+func (suite *cacheCmdTestSuite) TestPinInvalidConfigFile() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "cache", "pin", "abc.txt", "--config-file=cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid config file")
+}
+
+func (suite *cacheCmdTestSuite) TestPinUnpin() {
+	defer suite.cleanupTest()
+	pinFile := filepath.Join(suite.T().TempDir(), "pins")
+
+	_, err := executeCommandC(rootCmd, "cache", "pin", "datasets/**", "*.idx", "--pin-file="+pinFile)
+	suite.assert.Nil(err)
+	_, err = executeCommandC(rootCmd, "cache", "pin", "*.idx", "--pin-file="+pinFile)
+	suite.assert.Nil(err)
+
+	data, err := os.ReadFile(pinFile)
+	suite.assert.Nil(err)
+	suite.assert.Equal("datasets/**\n*.idx\n", string(data))
+
+	_, err = executeCommandC(rootCmd, "cache", "unpin", "datasets/**", "--pin-file="+pinFile)
+	suite.assert.Nil(err)
+	data, _ = os.ReadFile(pinFile)
+	suite.assert.Equal("*.idx\n", string(data))
+
+	op, err := executeCommandC(rootCmd, "cache", "unpin", "datasets/**", "--pin-file="+pinFile)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "none of the given globs is pinned")
+}
+
+func (suite *cacheCmdTestSuite) TestPinInvalidGlob() {
+	defer suite.cleanupTest()
+	pinFile := filepath.Join(suite.T().TempDir(), "pins")
+
+	_, err := executeCommandC(rootCmd, "cache", "pin", "  ", "--pin-file="+pinFile)
+	suite.assert.NotNil(err)
+	_, err = os.Stat(pinFile)
+	suite.assert.True(os.IsNotExist(err))
+}
+
+func (suite *cacheCmdTestSuite) TestInvalidateNotMounted() {
+	defer suite.cleanupTest()
+	listMountPoints = func() ([]string, error) { return []string{}, nil }
+
+	op, err := executeCommandC(rootCmd, "cache", "invalidate", suite.T().TempDir())
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "is not inside a blobfuse2 mount")
+}
+
+func (suite *cacheCmdTestSuite) TestInvalidate() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{"/other", mountPath}, nil }
+
+	var received admin.Request
+	admin.RegisterHandler(admin.VerbInvalidate, "test", func(req admin.Request) (string, error) {
+		received = req
+		return "done", nil
+	})
+	defer admin.UnregisterHandler(admin.VerbInvalidate, "test")
+
+	// Mount is not serving requests yet
+	_, err := executeCommandC(rootCmd, "cache", "invalidate", filepath.Join(mountPath, "dir"))
+	suite.assert.NotNil(err)
+
+	err = admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	_, err = executeCommandC(rootCmd, "cache", "invalidate", filepath.Join(mountPath, "dir", "sub"), "--recursive", "--file-cache")
+	suite.assert.Nil(err)
+	suite.assert.Equal(admin.Request{Verb: admin.VerbInvalidate, Path: "dir/sub", Recursive: true, FileCache: true}, received)
+}
@@ -0,0 +1,125 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/cobra"
+)
+
+// Time completion waits for storage to list the containers, so the shell does not hang on an unreachable account
+const completionTimeout = 5 * time.Second
+
+// completeMountPaths : Complete the mount points of blobfuse2, paths not among them are left to the shell
+func completeMountPaths(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	mntPts, _ := common.ListMountPoints()
+	matches := filterCompletions(mntPts, toComplete)
+	if len(matches) == 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContainers : Complete the containers of the account in the config given on the command line
+func completeContainers(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	listed := make(chan []string, 1)
+	go func() {
+		containers, err := listContainersForCompletion()
+		if err != nil {
+			cobra.CompDebugln(fmt.Sprintf("failed to list containers [%s]", err.Error()), false)
+		}
+		listed <- containers
+	}()
+
+	select {
+	case containers := <-listed:
+		return filterCompletions(containers, toComplete), cobra.ShellCompDirectiveNoFileComp
+	case <-time.After(completionTimeout):
+		cobra.CompDebugln("timed out listing containers", false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// listContainersForCompletion : List the containers with the credentials of the config file, env file and environment
+func listContainersForCompletion() ([]string, error) {
+	// Whatever goes wrong is for the debug log of the completion script, not for syslog
+	_ = log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_OFF()})
+
+	if options.EnvFile != "" {
+		err := loadEnvFile(common.ExpandPath(options.EnvFile))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.ConfigFile == "" {
+		if _, err := os.Stat(common.DefaultConfigFilePath); err == nil {
+			options.ConfigFile = common.DefaultConfigFilePath
+		}
+	}
+
+	if options.ConfigFile != "" {
+		err := parseConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The container may not be configured yet, it is what is being completed
+	config.SetBool("mount-all-containers", true)
+	return getContainerList()
+}
+
+// filterCompletions : Candidates starting with what was typed so far
+func filterCompletions(candidates []string, toComplete string) []string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
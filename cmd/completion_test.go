@@ -0,0 +1,106 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type completionTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *completionTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	options = mountOptions{}
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *completionTestSuite) cleanupTest() {
+	resetCLIFlags(*mountCmd)
+	resetCLIFlags(*unmountCmd)
+}
+
+func TestCompletion(t *testing.T) {
+	suite.Run(t, new(completionTestSuite))
+}
+
+func (suite *completionTestSuite) TestFilterCompletions() {
+	candidates := []string{"/mnt/data", "/mnt/logs", "/home/user/blob"}
+	suite.assert.Equal([]string{"/mnt/data", "/mnt/logs"}, filterCompletions(candidates, "/mnt/"))
+	suite.assert.Equal(candidates, filterCompletions(candidates, ""))
+	suite.assert.Empty(filterCompletions(candidates, "/srv"))
+}
+
+func (suite *completionTestSuite) TestParseArgsCompletionRequest() {
+	// Completion requests of the shell are not taken for a mount from /etc/fstab
+	for _, request := range []string{cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd} {
+		args := parseArgs([]string{"blobfuse2", request, "unmount", ""})
+		suite.assert.Equal([]string{request, "unmount", ""}, args)
+	}
+}
+
+func (suite *completionTestSuite) TestCompleteMountPaths() {
+	defer suite.cleanupTest()
+
+	// Paths which are no mount point of blobfuse2 are completed by the shell
+	op, err := executeCommandC(rootCmd, cobra.ShellCompRequestCmd, "unmount", "/no/such/mount")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, ":0\n")
+
+	// Only a single mount path is taken
+	op, err = executeCommandC(rootCmd, cobra.ShellCompRequestCmd, "unmount", "/mnt/data", "")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, ":4\n")
+}
+
+func (suite *completionTestSuite) TestCompleteContainersWithoutCredentials() {
+	defer suite.cleanupTest()
+
+	// Nothing to list the account with, so nothing is completed and neither are files offered
+	op, err := executeCommandC(rootCmd, cobra.ShellCompRequestCmd, "mount", suite.T().TempDir(), "--container-name", "")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, ":4\n")
+}
@@ -0,0 +1,106 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+
+	"github.com/spf13/cobra"
+)
+
+// topLevelOptions : Keys at the top level of the config which are read on their own instead of through mountOptions
+type topLevelOptions struct {
+	MountPath          string                  `config:"mount-path"`
+	AllowOther         bool                    `config:"allow-other"`
+	AllowRoot          bool                    `config:"allow-root"`
+	ReadOnly           bool                    `config:"read-only"`
+	InvalidateOnSync   bool                    `config:"invalidate-on-sync"`
+	PreMountValidate   bool                    `config:"pre-mount-validate"`
+	BasicRemountCheck  bool                    `config:"basic-remount-check"`
+	MountAll           containerListingOptions `config:"mountall"`
+	MountAllContainers bool                    `config:"mount-all-containers"`
+	Service            serviceOptions          `config:"service"`
+}
+
+// Section defining all the commands that inspect config files
+var configCmd = &cobra.Command{
+	Use:               "config",
+	Short:             "Validate config files and print their schema",
+	Long:              "Validate config files and print their schema",
+	SuggestFor:        []string{"conf", "cfg"},
+	Example:           "blobfuse2 config validate config.yaml\nblobfuse2 config schema > blobfuse2.schema.json",
+	FlagErrorHandling: cobra.ExitOnError,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:               "schema",
+	Short:             "Print the JSON schema of the config file",
+	Long:              "Print the JSON schema of the config file, built from the keys every component accepts. Editors can use it to complete and check config files as they are written.",
+	SuggestFor:        []string{"schma", "shema"},
+	Example:           "blobfuse2 config schema > blobfuse2.schema.json",
+	Args:              cobra.NoArgs,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	},
+}
+
+func init() {
+	config.RegisterSchema("", mountOptions{},
+		config.OneOf("logging.type", "syslog", "base", "silent", "default"),
+		config.OneOf("logging.level", "log_off", "log_crit", "log_err", "log_warning", "log_info", "log_trace", "log_debug"),
+		config.Deprecated("streaming", "add stream to components instead"),
+		config.Deprecated("use-attr-cache", "add attr_cache to components instead"),
+		config.Deprecated("libfuse-options", "use the keys of the libfuse section instead"),
+		config.AtLeast("supervisor.check-interval-sec", 1),
+		config.AtLeast("supervisor.hang-timeout-sec", 1),
+		config.AtLeast("health-probe.interval-sec", 1),
+		config.AtLeast("health-probe.timeout-sec", 1),
+		config.OneOf("preset", "general", "hpc", "ml-training", "backup"),
+		config.OneOf("logging.format", "text", "json"),
+		config.OneOf("shutdown-cache", "preserve", "cleanup"),
+		config.AtLeast("service.stop-timeout-sec", 1))
+	config.RegisterSchema("", topLevelOptions{})
+
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
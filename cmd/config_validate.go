@@ -0,0 +1,197 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type configValidateOptions struct {
+	JSON             bool
+	SecureConfig     bool
+	PassPhrase       string
+	PassphraseSource string
+}
+
+var configValidateOpts configValidateOptions
+
+// configValidateResult : Outcome of validating a config file as printed with --json
+type configValidateResult struct {
+	ConfigFile string                   `json:"configFile"`
+	Valid      bool                     `json:"valid"`
+	Problems   []config.ValidationError `json:"problems"`
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:               "validate <config file>",
+	Short:             "Check a config file for unknown keys and invalid values",
+	Long:              "Check a config file against the keys every component accepts. Values of the wrong type or out of range, options which can not be used together, deprecated keys and keys no component reads are reported, as a typo would otherwise silently fall back to the default. Exits with an error when the config is invalid, deprecated keys and sections of components not in the pipeline are only warnings.",
+	SuggestFor:        []string{"valdate", "vaildate", "lint"},
+	Example:           "blobfuse2 config validate config.yaml\nblobfuse2 config validate config.yaml --json",
+	Args:              cobra.ExactArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problems, err := validateConfigFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to validate config file [%s]", err.Error())
+		}
+
+		errCount := 0
+		for _, p := range problems {
+			if p.Severity == config.SeverityError {
+				errCount++
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if configValidateOpts.JSON {
+			data, err := json.MarshalIndent(configValidateResult{
+				ConfigFile: args[0],
+				Valid:      errCount == 0,
+				Problems:   problems,
+			}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, string(data))
+		} else {
+			printValidationErrors(out, problems)
+		}
+
+		if errCount > 0 {
+			return fmt.Errorf("%d error(s) found in %s", errCount, args[0])
+		}
+		return nil
+	},
+}
+
+// validateConfigFile : Decrypt the config file if needed and check it against the registered schemas
+func validateConfigFile(configFile string) ([]config.ValidationError, error) {
+	configFile = common.ExpandPath(configFile)
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if configValidateOpts.SecureConfig || filepath.Ext(configFile) == SecureConfigExtension {
+		passphrase, err := lookupPassphrase(configValidateOpts.PassPhrase, configValidateOpts.PassphraseSource)
+		if err != nil {
+			return nil, err
+		}
+		if passphrase == "" {
+			return nil, errors.New("no passphrase provided to decrypt the config file")
+		}
+
+		data, err = common.DecryptData(data, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt config file [%s]", err.Error())
+		}
+	}
+
+	conf := make(map[string]interface{})
+	err = yaml.Unmarshal(data, &conf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid yaml [%s]", err.Error())
+	}
+
+	conf, err = config.ResolveReferences(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := config.ValidateMap(conf)
+	return append(problems, validatePipeline(conf)...), nil
+}
+
+// validatePipeline : Every component listed has to be one this binary knows of
+func validatePipeline(conf map[string]interface{}) []config.ValidationError {
+	list, ok := conf["components"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	registered := internal.RegisteredComponents()
+
+	problems := make([]config.ValidationError, 0)
+	for i, c := range list {
+		name := fmt.Sprint(c)
+		if !contains(registered, name) {
+			problems = append(problems, config.ValidationError{
+				Key:      fmt.Sprintf("components[%d]", i),
+				Kind:     config.KindUnknown,
+				Severity: config.SeverityError,
+				Message:  fmt.Sprintf("unknown component %s, use one of %s", name, strings.Join(registered, ", ")),
+			})
+		}
+	}
+	return problems
+}
+
+func printValidationErrors(w io.Writer, problems []config.ValidationError) {
+	if len(problems) == 0 {
+		fmt.Fprintln(w, "config is valid")
+		return
+	}
+
+	for _, p := range problems {
+		fmt.Fprintf(w, "[%-7s] %-10s %s: %s\n", p.Severity, p.Kind, p.Key, p.Message)
+	}
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+
+	configValidateCmd.Flags().BoolVar(&configValidateOpts.JSON, "json", false,
+		"Print the problems found as json.")
+	configValidateCmd.Flags().BoolVar(&configValidateOpts.SecureConfig, "secure-config", false,
+		"Config file is encrypted, implied by the .azsec extension.")
+	configValidateCmd.Flags().StringVar(&configValidateOpts.PassPhrase, "passphrase", "",
+		"Key to decrypt the config file with. Can also be specified by env-variable BLOBFUSE2_SECURE_CONFIG_PASSPHRASE.")
+	configValidateCmd.Flags().StringVar(&configValidateOpts.PassphraseSource, "passphrase-source", "",
+		"Fetch the key from Azure Key Vault, keyvault://<vault>/<secret>[?client-id=<identity>], or the keyring of the OS, keyring://<key name>.")
+}
@@ -0,0 +1,194 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type configValidateTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *configValidateTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+}
+
+func (suite *configValidateTestSuite) cleanupTest() {
+	configValidateOpts = configValidateOptions{}
+	configValidateCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func (suite *configValidateTestSuite) writeConfig(name string, content []byte) string {
+	path := filepath.Join(suite.T().TempDir(), name)
+	err := os.WriteFile(path, content, 0644)
+	suite.assert.Nil(err)
+	return path
+}
+
+func TestConfigValidateCommand(t *testing.T) {
+	suite.Run(t, new(configValidateTestSuite))
+}
+
+func (suite *configValidateTestSuite) TestValidConfig() {
+	defer suite.cleanupTest()
+	path := suite.writeConfig("config.yaml", []byte(`
+allow-other: true
+logging:
+  type: syslog
+  level: log_debug
+components:
+  - libfuse
+  - file_cache
+  - attr_cache
+  - azstorage
+libfuse:
+  attribute-expiration-sec: 120
+file_cache:
+  path: /tmp/cache
+  timeout-sec: 120
+attr_cache:
+  timeout-sec: 7200
+azstorage:
+  type: block
+  account-name: myaccount
+  mode: key
+  container: mycontainer
+`))
+
+	op, err := executeCommandC(rootCmd, "config", "validate", path)
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "config is valid")
+}
+
+func (suite *configValidateTestSuite) TestInvalidConfig() {
+	defer suite.cleanupTest()
+	path := suite.writeConfig("config.yaml", []byte(`
+components:
+  - libfuse
+  - file_cahce
+libfuse:
+  kernel-cache: true
+  auto-cache: true
+file_cache:
+  timout-sec: 120
+  max-size-mb: lots
+  policy: fifo
+  file-cache-timeout-in-seconds: 10
+`))
+
+	op, err := executeCommandC(rootCmd, "config", "validate", path, "--json")
+	suite.assert.NotNil(err)
+
+	result := configValidateResult{}
+	suite.assert.Nil(json.NewDecoder(strings.NewReader(op)).Decode(&result))
+	suite.assert.False(result.Valid)
+
+	kinds := make(map[string]string)
+	for _, p := range result.Problems {
+		kinds[p.Key] = p.Kind
+	}
+	suite.assert.Equal(config.KindUnknown, kinds["components[1]"])
+	suite.assert.Equal(config.KindExclusive, kinds["libfuse.kernel-cache"])
+	suite.assert.Equal(config.KindUnknown, kinds["file_cache.timout-sec"])
+	suite.assert.Equal(config.KindType, kinds["file_cache.max-size-mb"])
+	suite.assert.Equal(config.KindRange, kinds["file_cache.policy"])
+	suite.assert.Equal(config.KindDeprecated, kinds["file_cache.file-cache-timeout-in-seconds"])
+}
+
+func (suite *configValidateTestSuite) TestWarningsOnly() {
+	defer suite.cleanupTest()
+	path := suite.writeConfig("config.yaml", []byte(`
+components:
+  - libfuse
+  - azstorage
+stream:
+  max-blocks-per-file: 3
+`))
+
+	op, err := executeCommandC(rootCmd, "config", "validate", path)
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "stream is not in components")
+	suite.assert.Contains(op, "max-blocks-per-file is deprecated")
+}
+
+func (suite *configValidateTestSuite) TestSecureConfig() {
+	defer suite.cleanupTest()
+	passphrase := "12312312312312312312312312312312"
+	cipherText, err := common.EncryptData([]byte("foreground: yes please\n"), []byte(passphrase))
+	suite.assert.Nil(err)
+	path := suite.writeConfig("config.yaml"+SecureConfigExtension, cipherText)
+
+	_, err = executeCommandC(rootCmd, "config", "validate", path)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "no passphrase")
+
+	op, err := executeCommandC(rootCmd, "config", "validate", path, "--passphrase="+passphrase)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "foreground")
+}
+
+func (suite *configValidateTestSuite) TestConfigFileNotFound() {
+	defer suite.cleanupTest()
+	_, err := executeCommandC(rootCmd, "config", "validate", "cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+}
+
+func (suite *configValidateTestSuite) TestSchema() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "config", "schema")
+	suite.assert.Nil(err)
+
+	schema := make(map[string]interface{})
+	suite.assert.Nil(json.Unmarshal([]byte(op), &schema))
+	props := schema["properties"].(map[string]interface{})
+	suite.assert.Contains(props, "file_cache")
+	suite.assert.Contains(props, "azstorage")
+	suite.assert.Contains(props, "allow-other")
+}
@@ -0,0 +1,311 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+)
+
+type cpOptions struct {
+	ConfigFile  string
+	Recursive   bool
+	Concurrency int
+}
+
+var cpOpts cpOptions
+
+// Marks a path in the container on the command line of cp
+const remotePrefix = "az:"
+
+// storageCopier : Part of the azstorage component cp needs, so tests can stand in for storage
+type storageCopier interface {
+	storageLister
+	CopyToFile(options internal.CopyToFileOptions) error
+	CopyFromFile(options internal.CopyFromFileOptions) error
+}
+
+// cpJob : One file to transfer
+type cpJob struct {
+	local  string
+	remote string
+	size   int64
+	mtime  time.Time
+}
+
+var cpCmd = &cobra.Command{
+	Use:               "cp <source> <destination>",
+	Short:             "Copy files between local disk and the container without mounting it",
+	Long:              "Upload or download files and directories straight through storage with the credentials of the config file, bypassing FUSE. Paths in the container are given as az:<path>, files are transferred in parallel. Useful to seed caches or to measure storage throughput without kernel overhead.",
+	SuggestFor:        []string{"copy", "azcopy"},
+	Example:           "blobfuse2 cp ./data az:datasets --recursive --config-file=config.yaml\nblobfuse2 cp az:datasets/train.csv /mnt/cache/ --config-file=config.yaml",
+	Args:              cobra.ExactArgs(2),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, dst := args[0], args[1]
+		upload := !strings.HasPrefix(src, remotePrefix) && strings.HasPrefix(dst, remotePrefix)
+		download := strings.HasPrefix(src, remotePrefix) && !strings.HasPrefix(dst, remotePrefix)
+		if !upload && !download {
+			return fmt.Errorf("exactly one of source and destination shall be in the container, given as %s<path>", remotePrefix)
+		}
+
+		if cpOpts.Concurrency < 1 {
+			return fmt.Errorf("concurrency shall be at least 1")
+		}
+
+		azComponent, err := getAzStorageComponent(cpOpts.ConfigFile)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = azComponent.Stop() }()
+
+		var jobs []cpJob
+		if upload {
+			jobs, err = uploadJobs(azComponent, common.ExpandPath(src), strings.TrimPrefix(dst, remotePrefix))
+		} else {
+			jobs, err = downloadJobs(azComponent, strings.TrimPrefix(src, remotePrefix), common.ExpandPath(dst))
+		}
+		if err != nil {
+			return err
+		}
+
+		return runCopy(cmd.OutOrStdout(), azComponent, jobs, upload)
+	},
+}
+
+// copyTarget : Where the source lands, inside the destination when it is an existing directory or ends in a slash
+// and at the destination itself otherwise, as with cp
+func copyTarget(dst string, dstIsDir bool, srcBase string, join func(elem ...string) string) string {
+	if dstIsDir {
+		return join(dst, srcBase)
+	}
+	return dst
+}
+
+// uploadJobs : Files to upload from the local source to the destination in the container
+func uploadJobs(azComponent storageCopier, src string, dst string) ([]cpJob, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() && !cpOpts.Recursive {
+		return nil, fmt.Errorf("%s is a directory, pass --recursive to copy it", src)
+	}
+
+	dstIsDir := dst == "" || strings.HasSuffix(dst, "/")
+	dst = cleanStoragePath(dst)
+	if !dstIsDir {
+		attr, err := azComponent.GetAttr(internal.GetAttrOptions{Name: dst})
+		dstIsDir = err == nil && attr.IsDir()
+	}
+	root := copyTarget(dst, dstIsDir, filepath.Base(src), path.Join)
+
+	if !info.IsDir() {
+		return []cpJob{{local: src, remote: root, size: info.Size()}}, nil
+	}
+
+	jobs := make([]cpJob, 0)
+	err = filepath.WalkDir(src, func(local string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(src, local)
+		jobs = append(jobs, cpJob{local: local, remote: path.Join(root, filepath.ToSlash(rel)), size: info.Size()})
+		return nil
+	})
+	return jobs, err
+}
+
+// downloadJobs : Files to download from the source in the container to the local destination
+func downloadJobs(azComponent storageCopier, src string, dst string) ([]cpJob, error) {
+	src = cleanStoragePath(src)
+	srcIsDir := src == ""
+	var attr *internal.ObjAttr
+	if !srcIsDir {
+		var err error
+		attr, err = azComponent.GetAttr(internal.GetAttrOptions{Name: src})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attributes of %s [%s]", src, err.Error())
+		}
+		srcIsDir = attr.IsDir()
+	}
+	if srcIsDir && !cpOpts.Recursive {
+		return nil, fmt.Errorf("%s is a directory, pass --recursive to copy it", src)
+	}
+
+	dstIsDir := strings.HasSuffix(dst, string(os.PathSeparator)) || strings.HasSuffix(dst, "/")
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		dstIsDir = true
+	}
+	srcBase := path.Base(src)
+	if src == "" {
+		srcBase = ""
+	}
+	root := copyTarget(filepath.Clean(dst), dstIsDir, srcBase, filepath.Join)
+
+	if !srcIsDir {
+		return []cpJob{{local: root, remote: src, size: attr.Size, mtime: attr.Mtime}}, nil
+	}
+
+	jobs := make([]cpJob, 0)
+	err := walkStorage(azComponent, src, true, func(attr *internal.ObjAttr) error {
+		if !attr.IsDir() {
+			rel := strings.TrimPrefix(strings.TrimPrefix(attr.Path, src), "/")
+			jobs = append(jobs, cpJob{local: filepath.Join(root, filepath.FromSlash(rel)), remote: attr.Path, size: attr.Size, mtime: attr.Mtime})
+		}
+		return nil
+	})
+	return jobs, err
+}
+
+// runCopy : Transfer the files, as many at a time as asked for, and report the throughput and the files which failed
+func runCopy(out io.Writer, azComponent storageCopier, jobs []cpJob, upload bool) error {
+	start := time.Now()
+	queue := make(chan cpJob)
+	var copied, bytes atomic.Int64
+	var failed []string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < cpOpts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				var err error
+				if upload {
+					err = uploadFile(azComponent, job)
+				} else {
+					err = downloadFile(azComponent, job)
+				}
+
+				if err != nil {
+					mu.Lock()
+					failed = append(failed, fmt.Sprintf("%s: %s", job.remote, err.Error()))
+					mu.Unlock()
+					continue
+				}
+				copied.Add(1)
+				bytes.Add(job.size)
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	fmt.Fprintf(out, "Copied %d files, %d bytes in %v (%.2f MB/s)\n", copied.Load(), bytes.Load(), elapsed.Round(time.Millisecond),
+		float64(bytes.Load())/float64(common.MbToBytes)/elapsed.Seconds())
+
+	if len(failed) > 0 {
+		for _, f := range failed {
+			fmt.Fprintln(out, "Failed", f)
+		}
+		return fmt.Errorf("%d files failed to copy", len(failed))
+	}
+	return nil
+}
+
+// uploadFile : Upload one local file to its path in the container
+func uploadFile(azComponent storageCopier, job cpJob) error {
+	f, err := os.Open(job.local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return azComponent.CopyFromFile(internal.CopyFromFileOptions{Name: job.remote, File: f})
+}
+
+// downloadFile : Download one file from the container, the local copy keeps the time it was last modified in storage
+func downloadFile(azComponent storageCopier, job cpJob) error {
+	err := os.MkdirAll(filepath.Dir(job.local), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(job.local, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = azComponent.CopyToFile(internal.CopyToFileOptions{Name: job.remote, Offset: 0, Count: job.size, File: f})
+	err = errors.Join(err, f.Close())
+	if err != nil {
+		return err
+	}
+
+	if !job.mtime.IsZero() {
+		_ = os.Chtimes(job.local, job.mtime, job.mtime)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+
+	cpCmd.Flags().StringVar(&cpOpts.ConfigFile, "config-file", "",
+		"Configures the path for the file where the account credentials are provided. Default is config.yaml in current directory.")
+	_ = cpCmd.MarkFlagFilename("config-file", "yaml")
+
+	cpCmd.Flags().BoolVarP(&cpOpts.Recursive, "recursive", "r", false, "Copy directories and everything under them")
+	cpCmd.Flags().IntVar(&cpOpts.Concurrency, "concurrency", 16, "Files transferred at a time")
+}
@@ -0,0 +1,221 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeCopier : Container held in memory, files hold their path as content unless uploaded
+type fakeCopier struct {
+	*fakeLister
+	sync.Mutex
+	data map[string][]byte
+}
+
+func newFakeCopier() *fakeCopier {
+	return &fakeCopier{fakeLister: testContainer(), data: make(map[string][]byte)}
+}
+
+func (f *fakeCopier) CopyToFile(options internal.CopyToFileOptions) error {
+	if options.Name == "e" {
+		return errors.New("download failed")
+	}
+	_, err := options.File.WriteString(options.Name)
+	return err
+}
+
+func (f *fakeCopier) CopyFromFile(options internal.CopyFromFileOptions) error {
+	data, err := io.ReadAll(options.File)
+	if err != nil {
+		return err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	f.data[options.Name] = data
+	return nil
+}
+
+func (f *fakeCopier) uploaded() []string {
+	names := make([]string, 0, len(f.data))
+	for name := range f.data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type cpCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *cpCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *cpCmdTestSuite) cleanupTest() {
+	cpOpts = cpOptions{Concurrency: 4}
+	cpCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestCpCommand(t *testing.T) {
+	suite.Run(t, new(cpCmdTestSuite))
+}
+
+func (suite *cpCmdTestSuite) TestCpBothLocal() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "cp", "a", "b")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "exactly one of source and destination")
+
+	op, err = executeCommandC(rootCmd, "cp", "az:a", "az:b")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "exactly one of source and destination")
+}
+
+func (suite *cpCmdTestSuite) TestCpInvalidConcurrency() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "cp", "a", "az:b", "--concurrency=0")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "concurrency shall be at least 1")
+}
+
+func (suite *cpCmdTestSuite) TestCpInvalidConfigFile() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "cp", "a", "az:b", "--config-file=cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid config file")
+}
+
+func (suite *cpCmdTestSuite) TestUpload() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+
+	src := filepath.Join(suite.T().TempDir(), "data")
+	suite.assert.Nil(os.MkdirAll(filepath.Join(src, "sub"), 0755))
+	suite.assert.Nil(os.WriteFile(filepath.Join(src, "x"), []byte("x data"), 0644))
+	suite.assert.Nil(os.WriteFile(filepath.Join(src, "sub", "y"), []byte("y data"), 0644))
+
+	// Directory needs --recursive
+	copier := newFakeCopier()
+	_, err := uploadJobs(copier, src, "dir")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "pass --recursive")
+
+	// Existing directory takes the source under its name
+	cpOpts.Recursive = true
+	jobs, err := uploadJobs(copier, src, "dir")
+	suite.assert.Nil(err)
+	out := &bytes.Buffer{}
+	err = runCopy(out, copier, jobs, true)
+	suite.assert.Nil(err)
+	suite.assert.Contains(out.String(), "Copied 2 files, 12 bytes")
+	suite.assert.Equal([]string{"dir/data/sub/y", "dir/data/x"}, copier.uploaded())
+	suite.assert.Equal([]byte("y data"), copier.data["dir/data/sub/y"])
+
+	// Path which does not exist becomes the copy
+	copier = newFakeCopier()
+	jobs, err = uploadJobs(copier, src, "backup")
+	suite.assert.Nil(err)
+	suite.assert.Nil(runCopy(out, copier, jobs, true))
+	suite.assert.Equal([]string{"backup/sub/y", "backup/x"}, copier.uploaded())
+
+	// Single file into the root of the container
+	copier = newFakeCopier()
+	jobs, err = uploadJobs(copier, filepath.Join(src, "x"), "")
+	suite.assert.Nil(err)
+	suite.assert.Nil(runCopy(out, copier, jobs, true))
+	suite.assert.Equal([]string{"x"}, copier.uploaded())
+}
+
+func (suite *cpCmdTestSuite) TestDownload() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	cpOpts.Recursive = true
+	dst := suite.T().TempDir()
+
+	copier := newFakeCopier()
+	jobs, err := downloadJobs(copier, "dir", dst)
+	suite.assert.Nil(err)
+	out := &bytes.Buffer{}
+	err = runCopy(out, copier, jobs, false)
+	suite.assert.Nil(err)
+	suite.assert.Contains(out.String(), "Copied 3 files")
+
+	data, err := os.ReadFile(filepath.Join(dst, "dir", "sub", "d"))
+	suite.assert.Nil(err)
+	suite.assert.Equal("dir/sub/d", string(data))
+	info, err := os.Stat(filepath.Join(dst, "dir", "b"))
+	suite.assert.Nil(err)
+	suite.assert.True(info.ModTime().Equal(copier.attrs["dir/b"].Mtime))
+
+	// Single file to a new name, a failed download is reported
+	jobs, err = downloadJobs(copier, "a", filepath.Join(dst, "renamed"))
+	suite.assert.Nil(err)
+	failing, err := downloadJobs(copier, "e", dst)
+	suite.assert.Nil(err)
+	out.Reset()
+	err = runCopy(out, copier, append(jobs, failing...), false)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "1 files failed to copy")
+	suite.assert.Contains(out.String(), "Failed e: download failed")
+	suite.assert.FileExists(filepath.Join(dst, "renamed"))
+
+	_, err = downloadJobs(copier, "missing", dst)
+	suite.assert.NotNil(err)
+}
@@ -0,0 +1,499 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/component/azstorage"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+)
+
+type doctorOptions struct {
+	ConfigFile string
+}
+
+var doctorOpts doctorOptions
+
+// Time allowed to connect to the storage endpoint
+const endpointDialTimeout = 5 * time.Second
+
+// checkStatus : Outcome of a single doctor check
+type checkStatus string
+
+const (
+	checkOK   checkStatus = "OK"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+	checkSkip checkStatus = "SKIP"
+)
+
+// finding : Result of one check along with what the user can do about it
+type finding struct {
+	Check  string
+	Status checkStatus
+	Detail string
+	Fix    string
+}
+
+// doctorReport : Findings of all checks in the order they were run
+type doctorReport struct {
+	findings []finding
+}
+
+func (r *doctorReport) add(check string, status checkStatus, detail string, fix string) {
+	r.findings = append(r.findings, finding{Check: check, Status: status, Detail: detail, Fix: fix})
+}
+
+func (r *doctorReport) count(status checkStatus) int {
+	n := 0
+	for _, f := range r.findings {
+		if f.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *doctorReport) print(w io.Writer) {
+	for _, f := range r.findings {
+		fmt.Fprintf(w, "[%-4s] %-10s %s\n", f.Status, f.Check, f.Detail)
+		if f.Fix != "" && (f.Status == checkFail || f.Status == checkWarn) {
+			fmt.Fprintf(w, "       %-10s fix: %s\n", "", f.Fix)
+		}
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:               "doctor [mount path]",
+	Short:             "Check the config, storage account and host for problems before mounting",
+	Long:              "Check the config, storage account and host for problems before mounting. Validates the config, resolves and connects to the storage endpoint, obtains a credential, lists the container, verifies fuse is usable and the cache directories are writable. Every problem found is reported along with how to fix it.\nIf a mount path is given it is checked as well.",
+	SuggestFor:        []string{"doctr", "check", "diagnose"},
+	Example:           "blobfuse2 doctor --config-file=config.yaml\nblobfuse2 doctor ~/mount_path --config-file=config.yaml",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeMountPaths,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report := &doctorReport{}
+
+		opts := checkConfig(report, doctorOpts.ConfigFile)
+		if opts != nil {
+			checkStorage(report, opts)
+		} else {
+			report.add("storage", checkSkip, "config could not be loaded", "")
+		}
+
+		checkFuse(report, opts)
+		checkCacheDirs(report, opts)
+
+		if len(args) > 0 {
+			checkMountPath(report, args[0], opts)
+		}
+
+		out := cmd.OutOrStdout()
+		report.print(out)
+
+		failed := report.count(checkFail)
+		if failed > 0 {
+			return fmt.Errorf("%d check(s) failed, fix them before mounting", failed)
+		}
+
+		fmt.Fprintf(out, "\nNo problems found, %d warning(s)\n", report.count(checkWarn))
+		return nil
+	},
+}
+
+// checkConfig : Load the config file and validate the pipeline it describes, returns nil if it can not be used
+func checkConfig(report *doctorReport, configFile string) *mountOptions {
+	options.ConfigFile = configFile
+	if options.ConfigFile == "" {
+		if _, err := os.Stat(common.DefaultConfigFilePath); err != nil {
+			report.add("config", checkFail, "no config file given",
+				"pass --config-file or keep config.yaml in the current directory")
+			return nil
+		}
+		options.ConfigFile = common.DefaultConfigFilePath
+	}
+
+	err := parseConfig()
+	if err != nil {
+		report.add("config", checkFail, err.Error(),
+			"make sure the file exists and is valid YAML, encrypted configs need the passphrase in "+SecureConfigEnvName)
+		return nil
+	}
+
+	opts := &mountOptions{}
+	err = config.Unmarshal(opts)
+	if err != nil {
+		report.add("config", checkFail, err.Error(), "fix the type of the value reported above")
+		return nil
+	}
+
+	if config.IsSet("logging.level") {
+		if err = common.ELogLevel.Parse(opts.Logging.LogLevel); err != nil {
+			report.add("config", checkFail, fmt.Sprintf("invalid log level %s", opts.Logging.LogLevel),
+				"use one of LOG_OFF, LOG_CRIT, LOG_ERR, LOG_WARNING, LOG_INFO, LOG_DEBUG or LOG_TRACE")
+			return nil
+		}
+	}
+
+	if len(opts.Components) == 0 {
+		report.add("config", checkFail, "no components configured",
+			"add a components list, for example [libfuse, file_cache, attr_cache, azstorage]")
+		return nil
+	}
+
+	registered := internal.RegisteredComponents()
+	for _, name := range opts.Components {
+		if !contains(registered, name) {
+			report.add("config", checkFail, fmt.Sprintf("component %s is not known", name),
+				fmt.Sprintf("use one of %s", strings.Join(registered, ", ")))
+			return nil
+		}
+	}
+
+	for _, name := range registered {
+		if !contains(opts.Components, name) && config.IsSet(name) {
+			report.add("config", checkWarn, fmt.Sprintf("%s is configured but not in components, its settings are ignored", name),
+				fmt.Sprintf("add %s to components or remove its section", name))
+		}
+	}
+
+	report.add("config", checkOK, fmt.Sprintf("%s loaded, pipeline %s", options.ConfigFile, strings.Join(opts.Components, " -> ")), "")
+	return opts
+}
+
+// checkStorage : Reach the storage endpoint, obtain a credential and list the container with it
+func checkStorage(report *doctorReport, opts *mountOptions) {
+	if !contains(opts.Components, "azstorage") {
+		report.add("storage", checkSkip, "azstorage is not in components", "")
+		return
+	}
+
+	conf := azstorage.AzStorageOptions{}
+	_ = config.UnmarshalKey("azstorage", &conf)
+
+	azComponent := &azstorage.AzStorage{}
+	azComponent.SetName("azstorage")
+	azComponent.SetNextComponent(nil)
+
+	// Credentials of spn and msi are obtained while the pipeline is set up, skip listing the container here
+	authErr := azComponent.Configure(false)
+	if authErr != nil && strings.HasPrefix(authErr.Error(), "config error") {
+		report.add("storage", checkFail, authErr.Error(), "fix the azstorage section of the config")
+		return
+	}
+
+	checkEndpoint(report, azComponent.Endpoint(), conf)
+
+	if authErr != nil {
+		report.add("auth", checkFail, authErr.Error(), authFix(conf.AuthMode))
+		report.add("container", checkSkip, "no credential to list the container with", "")
+		return
+	}
+	report.add("auth", checkOK, fmt.Sprintf("obtained credential for %s", authModeName(conf)), "")
+
+	err := azComponent.CheckContainer()
+	if err != nil {
+		detail, fix := classifyStorageError(err, conf)
+		report.add("container", checkFail, detail, fix)
+		return
+	}
+	report.add("container", checkOK, fmt.Sprintf("listed container %s", conf.Container), "")
+
+	readOnly := false
+	_ = config.UnmarshalKey("read-only", &readOnly)
+	if authModeName(conf) == "sas" && !readOnly {
+		missing := missingSASPermissions(conf.SaSKey)
+		if missing != "" {
+			report.add("container", checkWarn, fmt.Sprintf("sas does not grant %s permission, writes will fail", missing),
+				"generate a sas with read, add, create, write, delete and list permissions or mount with read-only")
+		}
+	}
+}
+
+// checkEndpoint : Resolve the storage endpoint and open a connection to it
+func checkEndpoint(report *doctorReport, endpoint string, conf azstorage.AzStorageOptions) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		report.add("endpoint", checkFail, fmt.Sprintf("invalid endpoint %s", endpoint), "fix azstorage.endpoint or azstorage.account-name")
+		return
+	}
+
+	host := u.Hostname()
+	if _, err = net.LookupHost(host); err != nil {
+		report.add("endpoint", checkFail, fmt.Sprintf("failed to resolve %s [%s]", host, err.Error()),
+			"check azstorage.account-name and the DNS setup of this host, private endpoints need their private DNS zone")
+		return
+	}
+
+	proxy := conf.HttpsProxyAddress
+	if conf.UseHTTP && conf.HttpProxyAddress != "" {
+		proxy = conf.HttpProxyAddress
+	}
+	if proxy != "" {
+		report.add("endpoint", checkOK, fmt.Sprintf("resolved %s, connections go through proxy %s", host, proxy), "")
+		return
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), endpointDialTimeout)
+	if err != nil {
+		report.add("endpoint", checkFail, fmt.Sprintf("failed to connect to %s:%s [%s]", host, port, err.Error()),
+			"check firewall rules and storage account network settings, configure azstorage.https-proxy if a proxy is required")
+		return
+	}
+	_ = conn.Close()
+
+	report.add("endpoint", checkOK, fmt.Sprintf("connected to %s:%s", host, port), "")
+}
+
+// authModeName : Auth mode in effect for the given options, as azstorage decides it when none is configured
+func authModeName(conf azstorage.AzStorageOptions) string {
+	if conf.AuthMode != "" {
+		return strings.ToLower(conf.AuthMode)
+	}
+
+	switch {
+	case conf.AccountKey != "":
+		return "key"
+	case conf.SaSKey != "":
+		return "sas"
+	case conf.ClientID != "" || conf.ClientSecret != "" || conf.TenantID != "":
+		return "spn"
+	default:
+		return "msi"
+	}
+}
+
+// authFix : What to look at when no credential could be obtained for the given auth mode
+func authFix(mode string) string {
+	switch strings.ToLower(mode) {
+	case "spn":
+		return "check clientid, tenantid and clientsecret or oauth-token-path, and that aadendpoint is reachable"
+	case "msi":
+		return "check the identity is assigned to this VM and appid, resid or objid match it"
+	default:
+		return "check the credentials in the azstorage section of the config"
+	}
+}
+
+// classifyStorageError : Turn the error of listing the container into a finding the user can act on
+func classifyStorageError(err error, conf azstorage.AzStorageOptions) (string, string) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "ContainerNotFound") || strings.Contains(msg, "FilesystemNotFound"):
+		return fmt.Sprintf("container %s does not exist", conf.Container),
+			"create the container or fix azstorage.container"
+	case strings.Contains(msg, "AuthorizationPermissionMismatch") || strings.Contains(msg, "AuthorizationFailure") ||
+		strings.Contains(msg, "AuthorizationResourceTypeMismatch"):
+		return "credential is not permitted to list the container",
+			"assign the Storage Blob Data Contributor role to the identity, or use a sas with list permission, and allow this network in the account firewall"
+	case strings.Contains(msg, "AuthenticationFailed") || strings.Contains(msg, "InvalidAuthenticationInfo"):
+		return "storage rejected the credential",
+			"check the account key or sas is current and the clock of this host is in sync"
+	default:
+		return fmt.Sprintf("failed to list container %s [%s]", conf.Container, strings.SplitN(msg, "\n", 2)[0]),
+			"check the account and container names in the config"
+	}
+}
+
+// missingSASPermissions : Permissions a read-write mount needs which the sas does not grant
+func missingSASPermissions(sas string) string {
+	values, err := url.ParseQuery(strings.TrimPrefix(sas, "?"))
+	if err != nil {
+		return ""
+	}
+
+	granted := values.Get("sp")
+	missing := ""
+	for _, p := range "acwdl" {
+		if !strings.ContainsRune(granted, p) {
+			missing += string(p)
+		}
+	}
+	return missing
+}
+
+// checkFuse : Make sure fuse is usable by the current user
+func checkFuse(report *doctorReport, opts *mountOptions) {
+	if runtime.GOOS != "linux" {
+		report.add("fuse", checkSkip, fmt.Sprintf("not checked on %s", runtime.GOOS), "")
+		return
+	}
+
+	dev, err := os.OpenFile("/dev/fuse", os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		report.add("fuse", checkFail, "/dev/fuse does not exist",
+			"install fuse3 and load the fuse module with 'modprobe fuse', containers need --device /dev/fuse --cap-add SYS_ADMIN")
+		return
+	} else if err != nil {
+		report.add("fuse", checkFail, fmt.Sprintf("failed to open /dev/fuse [%s]", err.Error()),
+			"add the user to the group owning /dev/fuse or fix its permissions")
+		return
+	}
+	_ = dev.Close()
+
+	helper := ""
+	for _, name := range []string{"fusermount3", "fusermount"} {
+		if path, err := exec.LookPath(name); err == nil {
+			helper = path
+			break
+		}
+	}
+	if helper == "" {
+		status := checkFail
+		if os.Geteuid() == 0 {
+			status = checkWarn
+		}
+		report.add("fuse", status, "fusermount3 or fusermount not found in PATH",
+			"install the fuse3 package, it is needed to mount as a regular user and to unmount")
+		return
+	}
+
+	allowOther := false
+	_ = config.UnmarshalKey("allow-other", &allowOther)
+	if opts != nil && allowOther && os.Geteuid() != 0 && !common.FuseConfAllowsOther("/etc/fuse.conf") {
+		report.add("fuse", checkFail, "allow-other is set but user_allow_other is not enabled in /etc/fuse.conf",
+			"uncomment user_allow_other in /etc/fuse.conf or drop allow-other")
+		return
+	}
+
+	report.add("fuse", checkOK, fmt.Sprintf("/dev/fuse is usable, helper %s", helper), "")
+}
+
+// checkCacheDirs : Make sure the work directory and cache directories can be written to
+func checkCacheDirs(report *doctorReport, opts *mountOptions) {
+	workDir := common.DefaultWorkDir
+	if opts != nil && opts.DefaultWorkingDir != "" {
+		workDir = opts.DefaultWorkingDir
+	}
+	checkDirWritable(report, "work-dir", common.ExpandPath(workDir))
+
+	if opts != nil && contains(opts.Components, "file_cache") {
+		tmpPath := ""
+		_ = config.UnmarshalKey("file_cache.path", &tmpPath)
+		if tmpPath == "" {
+			report.add("cache-dir", checkFail, "file_cache.path is not set", "set file_cache.path to a directory on a local disk")
+			return
+		}
+		checkDirWritable(report, "cache-dir", common.ExpandPath(tmpPath))
+	}
+}
+
+// checkDirWritable : Create and remove a file in the given directory, or its closest existing parent if it is yet to be created
+func checkDirWritable(report *doctorReport, check string, dir string) {
+	probeDir := filepath.Clean(dir)
+	for {
+		if _, err := os.Stat(probeDir); err == nil {
+			break
+		}
+		parent := filepath.Dir(probeDir)
+		if parent == probeDir {
+			break
+		}
+		probeDir = parent
+	}
+
+	f, err := os.CreateTemp(probeDir, ".blobfuse2-doctor-*")
+	if err != nil {
+		report.add(check, checkFail, fmt.Sprintf("%s is not writable [%s]", probeDir, err.Error()),
+			fmt.Sprintf("give the user running blobfuse2 write access to %s or choose another directory", probeDir))
+		return
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+
+	if probeDir != filepath.Clean(dir) {
+		report.add(check, checkOK, fmt.Sprintf("%s does not exist yet, it can be created", dir), "")
+		return
+	}
+	report.add(check, checkOK, fmt.Sprintf("%s is writable", dir), "")
+}
+
+// checkMountPath : Make sure the given path can be mounted on
+func checkMountPath(report *doctorReport, mountPath string, opts *mountOptions) {
+	mountPath = common.ExpandPath(mountPath)
+
+	nonEmpty := false
+	if opts != nil {
+		nonEmpty = opts.NonEmpty
+	}
+
+	err := validateMountPath(mountPath, nonEmpty)
+	if err != nil {
+		report.add("mount-path", checkFail, fmt.Sprintf("%s: %s", mountPath, err.Error()),
+			"use an existing empty directory which is not mounted yet, or set nonempty to mount over files")
+		return
+	}
+	report.add("mount-path", checkOK, fmt.Sprintf("%s can be mounted on", mountPath), "")
+}
+
+func contains(list []string, item string) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringVar(&doctorOpts.ConfigFile, "config-file", "",
+		"Configures the path for the file where the account credentials are provided. Default is config.yaml in current directory.")
+	_ = doctorCmd.MarkFlagFilename("config-file", "yaml")
+}
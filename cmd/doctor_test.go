@@ -0,0 +1,174 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/component/azstorage"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type doctorTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *doctorTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *doctorTestSuite) cleanupTest() {
+	doctorOpts = doctorOptions{}
+	doctorCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func (suite *doctorTestSuite) writeConfig(content string) string {
+	path := filepath.Join(suite.T().TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(content), 0644)
+	suite.assert.Nil(err)
+	return path
+}
+
+func TestDoctorCommand(t *testing.T) {
+	suite.Run(t, new(doctorTestSuite))
+}
+
+func (suite *doctorTestSuite) TestDoctorHelp() {
+	defer suite.cleanupTest()
+	_, err := executeCommandC(rootCmd, "doctor", "-h")
+	suite.assert.Nil(err)
+}
+
+func (suite *doctorTestSuite) TestDoctorInvalidConfigFile() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "doctor", "--config-file=cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "[FAIL] config")
+	suite.assert.Contains(op, "[SKIP] storage")
+}
+
+func (suite *doctorTestSuite) TestDoctorTooManyArgs() {
+	defer suite.cleanupTest()
+	_, err := executeCommandC(rootCmd, "doctor", "a", "b")
+	suite.assert.NotNil(err)
+}
+
+func (suite *doctorTestSuite) TestCheckConfigUnknownComponent() {
+	defer suite.cleanupTest()
+	report := &doctorReport{}
+	opts := checkConfig(report, suite.writeConfig("components:\n  - libfuse\n  - blob_cache\n"))
+	suite.assert.Nil(opts)
+	suite.assert.Equal(1, report.count(checkFail))
+	suite.assert.Contains(report.findings[0].Detail, "blob_cache")
+}
+
+func (suite *doctorTestSuite) TestCheckConfigNoComponents() {
+	defer suite.cleanupTest()
+	report := &doctorReport{}
+	opts := checkConfig(report, suite.writeConfig("foreground: true\n"))
+	suite.assert.Nil(opts)
+	suite.assert.Contains(report.findings[0].Detail, "no components")
+}
+
+func (suite *doctorTestSuite) TestCheckConfigIgnoredSection() {
+	defer suite.cleanupTest()
+	report := &doctorReport{}
+	opts := checkConfig(report, suite.writeConfig("components:\n  - loopbackfs\nloopbackfs:\n  path: /tmp\nfile_cache:\n  path: /tmp\n"))
+	suite.assert.NotNil(opts)
+	suite.assert.Equal(0, report.count(checkFail))
+	suite.assert.Equal(1, report.count(checkWarn))
+	suite.assert.Contains(report.findings[0].Detail, "file_cache is configured but not in components")
+
+	checkStorage(report, opts)
+	suite.assert.Equal(checkSkip, report.findings[len(report.findings)-1].Status)
+}
+
+func (suite *doctorTestSuite) TestCheckDirWritable() {
+	defer suite.cleanupTest()
+	dir := suite.T().TempDir()
+
+	report := &doctorReport{}
+	checkDirWritable(report, "cache-dir", dir)
+	checkDirWritable(report, "cache-dir", filepath.Join(dir, "a", "b"))
+	suite.assert.Equal(2, report.count(checkOK))
+	suite.assert.Contains(report.findings[1].Detail, "does not exist yet")
+
+	entries, err := os.ReadDir(dir)
+	suite.assert.Nil(err)
+	suite.assert.Empty(entries)
+}
+
+func (suite *doctorTestSuite) TestCheckMountPathNotExist() {
+	defer suite.cleanupTest()
+	report := &doctorReport{}
+	checkMountPath(report, filepath.Join(suite.T().TempDir(), "missing"), nil)
+	suite.assert.Equal(1, report.count(checkFail))
+}
+
+func (suite *doctorTestSuite) TestMissingSASPermissions() {
+	defer suite.cleanupTest()
+	suite.assert.Equal("", missingSASPermissions("?sv=2021-06-08&sp=racwdl&sig=abc"))
+	suite.assert.Equal("acwd", missingSASPermissions("sv=2021-06-08&sp=rl&sig=abc"))
+}
+
+func (suite *doctorTestSuite) TestClassifyStorageError() {
+	defer suite.cleanupTest()
+	conf := azstorage.AzStorageOptions{Container: "data"}
+
+	detail, fix := classifyStorageError(errors.New("===== RESPONSE ERROR (ServiceCode=ContainerNotFound) ====="), conf)
+	suite.assert.Contains(detail, "container data does not exist")
+	suite.assert.NotEmpty(fix)
+
+	detail, _ = classifyStorageError(errors.New("ServiceCode=AuthorizationPermissionMismatch"), conf)
+	suite.assert.Contains(detail, "not permitted")
+
+	detail, _ = classifyStorageError(errors.New("ServiceCode=AuthenticationFailed"), conf)
+	suite.assert.Contains(detail, "rejected")
+}
@@ -0,0 +1,188 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+)
+
+type duOptions struct {
+	ConfigFile    string
+	MaxDepth      int
+	HumanReadable bool
+	Output        string
+}
+
+var duOpts duOptions
+
+// duEntry : Size of a directory and everything under it
+type duEntry struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"`
+	Files int64  `json:"files"`
+}
+
+var duCmd = &cobra.Command{
+	Use:               "du [path]",
+	Short:             "Show the size of directories in the container without mounting it",
+	Long:              "Show the size and number of files of the container, or of the given path, and of the directories under it down to --max-depth, straight from storage with the credentials of the config file. Nothing is mounted, so the data can be looked at before mounting it.",
+	SuggestFor:        []string{"size", "usage"},
+	Example:           "blobfuse2 du --config-file=config.yaml\nblobfuse2 du datasets --max-depth=1 --human-readable --config-file=config.yaml",
+	Args:              cobra.MaximumNArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if duOpts.Output != "table" && duOpts.Output != "json" {
+			return fmt.Errorf("invalid output format %s, shall be table or json", duOpts.Output)
+		}
+
+		if duOpts.MaxDepth < 0 {
+			return fmt.Errorf("max-depth shall not be negative")
+		}
+
+		name := ""
+		if len(args) > 0 {
+			name = cleanStoragePath(args[0])
+		}
+
+		azComponent, err := getAzStorageComponent(duOpts.ConfigFile)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = azComponent.Stop() }()
+
+		return diskUsage(cmd.OutOrStdout(), azComponent, name)
+	},
+}
+
+// diskUsage : Print the size of the path and of the directories under it, children before their parent as du does
+func diskUsage(out io.Writer, lister storageLister, name string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	emit := func(entry duEntry) error {
+		if duOpts.Output == "json" {
+			return json.NewEncoder(out).Encode(entry)
+		}
+
+		size := fmt.Sprintf("%d", entry.Size)
+		if duOpts.HumanReadable {
+			size = humanSize(entry.Size)
+		}
+		_, err := fmt.Fprintf(w, "%s\t%d\t%s\n", size, entry.Files, entry.Path)
+		return err
+	}
+
+	if name != "" {
+		attr, err := lister.GetAttr(internal.GetAttrOptions{Name: name})
+		if err != nil {
+			return fmt.Errorf("failed to get attributes of %s [%s]", name, err.Error())
+		}
+
+		if !attr.IsDir() {
+			if err = emit(duEntry{Path: name, Size: attr.Size, Files: 1}); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+	}
+
+	_, err := dirUsage(lister, name, 0, emit)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// dirUsage : Size of the directory summed up over everything under it, directories down to the max depth are reported
+func dirUsage(lister storageLister, name string, depth int, emit func(duEntry) error) (duEntry, error) {
+	total := duEntry{Path: name}
+	if total.Path == "" {
+		total.Path = "/"
+	}
+
+	err := walkStorage(lister, name, false, func(attr *internal.ObjAttr) error {
+		if !attr.IsDir() {
+			total.Size += attr.Size
+			total.Files++
+			return nil
+		}
+
+		sub, err := dirUsage(lister, attr.Path, depth+1, emit)
+		total.Size += sub.Size
+		total.Files += sub.Files
+		return err
+	})
+	if err != nil {
+		return total, err
+	}
+
+	if depth <= duOpts.MaxDepth {
+		err = emit(total)
+	}
+	return total, err
+}
+
+// humanSize : Size in powers of 1024 with a unit suffix, like du -h
+func humanSize(size int64) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	value := float64(size)
+	unit := -1
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f%c", value, units[unit])
+}
+
+func init() {
+	rootCmd.AddCommand(duCmd)
+
+	duCmd.Flags().StringVar(&duOpts.ConfigFile, "config-file", "",
+		"Configures the path for the file where the account credentials are provided. Default is config.yaml in current directory.")
+	_ = duCmd.MarkFlagFilename("config-file", "yaml")
+
+	duCmd.Flags().IntVarP(&duOpts.MaxDepth, "max-depth", "d", 0,
+		"Report directories this many levels under the path as well, 0 reports the path only")
+	duCmd.Flags().BoolVarP(&duOpts.HumanReadable, "human-readable", "H", false, "Print sizes like 1.5K, 20.0M and 3.2G")
+	duCmd.Flags().StringVar(&duOpts.Output, "output", "table", "Output format, table or json (one object per line)")
+}
@@ -0,0 +1,144 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type duCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *duCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *duCmdTestSuite) cleanupTest() {
+	duOpts = duOptions{Output: "table"}
+	duCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestDuCommand(t *testing.T) {
+	suite.Run(t, new(duCmdTestSuite))
+}
+
+func (suite *duCmdTestSuite) TestDuInvalidDepth() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "du", "--max-depth=-1")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "max-depth shall not be negative")
+}
+
+func (suite *duCmdTestSuite) TestDuInvalidConfigFile() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "du", "--config-file=cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid config file")
+}
+
+func (suite *duCmdTestSuite) TestHumanSize() {
+	suite.assert.Equal("512B", humanSize(512))
+	suite.assert.Equal("1.0K", humanSize(1024))
+	suite.assert.Equal("1.5M", humanSize(3*512*1024))
+	suite.assert.Equal("2.0G", humanSize(2*1024*1024*1024))
+}
+
+func (suite *duCmdTestSuite) TestDu() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	out := &bytes.Buffer{}
+
+	err := diskUsage(out, testContainer(), "")
+	suite.assert.Nil(err)
+	suite.assert.Equal([]string{"7183", "5", "/"}, strings.Fields(out.String()))
+
+	out.Reset()
+	err = diskUsage(out, testContainer(), "dir/b")
+	suite.assert.Nil(err)
+	suite.assert.Equal([]string{"1024", "1", "dir/b"}, strings.Fields(out.String()))
+}
+
+func (suite *duCmdTestSuite) TestDuMaxDepth() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	duOpts.MaxDepth, duOpts.HumanReadable = 1, true
+	out := &bytes.Buffer{}
+
+	// Children are reported before their parent, the directory below max depth is summed up only
+	err := diskUsage(out, testContainer(), "")
+	suite.assert.Nil(err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	suite.assert.Len(lines, 2)
+	suite.assert.Equal([]string{"7.0K", "3", "dir"}, strings.Fields(lines[0]))
+	suite.assert.Equal([]string{"7.0K", "5", "/"}, strings.Fields(lines[1]))
+}
+
+func (suite *duCmdTestSuite) TestDuJSON() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	duOpts.Output, duOpts.MaxDepth = "json", 2
+	out := &bytes.Buffer{}
+
+	err := diskUsage(out, testContainer(), "dir")
+	suite.assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	suite.assert.Len(lines, 2)
+	entry := duEntry{}
+	err = json.Unmarshal([]byte(lines[0]), &entry)
+	suite.assert.Nil(err)
+	suite.assert.Equal(duEntry{Path: "dir/sub", Size: 4096, Files: 1}, entry)
+	err = json.Unmarshal([]byte(lines[1]), &entry)
+	suite.assert.Nil(err)
+	suite.assert.Equal(duEntry{Path: "dir", Size: 7168, Files: 3}, entry)
+}
@@ -0,0 +1,128 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+type eventsOptions struct {
+	Recursive bool
+	Follow    bool
+}
+
+var eventsOpts eventsOptions
+
+var eventsCmd = &cobra.Command{
+	Use:               "events <path>",
+	Short:             "Print changes made by other clients to a directory of a running mount",
+	Long:              "Print changes made by other clients to a directory of a running mount, one JSON object per line, so applications can react to files created, modified or deleted in the container without polling it. The directory is watched by the mount from the first request on when attr_cache has watch-interval-sec set, changes found while serving the mount are reported in any case.",
+	SuggestFor:        []string{"event", "watch"},
+	Example:           "blobfuse2 events ~/mount_path/incoming --follow",
+	Args:              cobra.ExactArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mountPath, name, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		since := uint64(0)
+		for {
+			since, err = printRemoteChanges(cmd.OutOrStdout(), mountPath, admin.Request{
+				Verb:      admin.VerbEvents,
+				Path:      name,
+				Recursive: eventsOpts.Recursive,
+				Since:     since,
+				Wait:      eventsOpts.Follow,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get events of %s [%s]", args[0], err.Error())
+			}
+
+			if !eventsOpts.Follow {
+				return nil
+			}
+		}
+	},
+}
+
+// printRemoteChanges : Print the changes the mount reports for the request, one per line, and return the sequence
+// number to ask for changes after next time
+func printRemoteChanges(w io.Writer, mountPath string, req admin.Request) (uint64, error) {
+	resp, err := admin.Send(mountPath, req)
+	if err != nil {
+		return req.Since, err
+	}
+
+	// Message is the answer of the component watching the mount, as "<component>: <changes>"
+	_, data, found := strings.Cut(resp.Message, ": ")
+	if !found {
+		return req.Since, fmt.Errorf("unexpected response %q", resp.Message)
+	}
+
+	changes := []internal.RemoteChange{}
+	err = json.Unmarshal([]byte(data), &changes)
+	if err != nil {
+		return req.Since, err
+	}
+
+	since := req.Since
+	encoder := json.NewEncoder(w)
+	for _, change := range changes {
+		err = encoder.Encode(change)
+		if err != nil {
+			return since, err
+		}
+		since = change.Seq
+	}
+	return since, nil
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().BoolVar(&eventsOpts.Recursive, "recursive", false,
+		"Print changes of everything under the directory, not only of its children")
+	eventsCmd.Flags().BoolVarP(&eventsOpts.Follow, "follow", "f", false,
+		"Keep printing changes as the mount finds them, till interrupted")
+}
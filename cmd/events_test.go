@@ -0,0 +1,131 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type eventsCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *eventsCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *eventsCmdTestSuite) cleanupTest() {
+	eventsOpts = eventsOptions{}
+	listMountPoints = common.ListMountPoints
+	eventsCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func (suite *eventsCmdTestSuite) TestEventsNotMounted() {
+	defer suite.cleanupTest()
+	listMountPoints = func() ([]string, error) { return []string{}, nil }
+
+	op, err := executeCommandC(rootCmd, "events", suite.T().TempDir())
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "is not inside a blobfuse2 mount")
+}
+
+func (suite *eventsCmdTestSuite) TestEvents() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	changes := []internal.RemoteChange{
+		{Seq: 7, Time: time.Now().UTC(), Kind: internal.RemoteCreated, Path: "dir/a"},
+		{Seq: 9, Time: time.Now().UTC(), Kind: internal.RemoteDeleted, Path: "dir/b"},
+	}
+	var received admin.Request
+	admin.RegisterHandler(admin.VerbEvents, "attr_cache", func(req admin.Request) (string, error) {
+		received = req
+		data, err := json.Marshal(changes)
+		return string(data), err
+	})
+	defer admin.UnregisterHandler(admin.VerbEvents, "attr_cache")
+
+	err := admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	_, err = executeCommandC(rootCmd, "events", filepath.Join(mountPath, "dir"), "--recursive")
+	suite.assert.Nil(err)
+	suite.assert.Equal(admin.Request{Verb: admin.VerbEvents, Path: "dir", Recursive: true}, received)
+
+	// Changes are printed one per line and asked for after the last one next time
+	out := &bytes.Buffer{}
+	since, err := printRemoteChanges(out, mountPath, admin.Request{Verb: admin.VerbEvents, Path: "dir", Since: 5})
+	suite.assert.Nil(err)
+	suite.assert.EqualValues(9, since)
+	suite.assert.Equal(5, int(received.Since))
+
+	expected := ""
+	for _, change := range changes {
+		data, _ := json.Marshal(change)
+		expected += fmt.Sprintf("%s\n", data)
+	}
+	suite.assert.Equal(expected, out.String())
+}
+
+func TestEventsCommand(t *testing.T) {
+	suite.Run(t, new(eventsCmdTestSuite))
+}
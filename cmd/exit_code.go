@@ -0,0 +1,175 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of blobfuse2, stable across releases so automation can branch on why mount or unmount failed
+const (
+	exitSuccess            = 0
+	exitFailure            = 1  // Anything not told apart below
+	exitUsage              = 2  // Invalid flags or arguments
+	exitConfig             = 3  // Config file, environment or options can not be read or are invalid
+	exitAuth               = 4  // Storage rejected the credentials
+	exitContainerMissing   = 5  // Credentials were accepted but the container does not exist
+	exitFuseUnavailable    = 6  // Fuse can not mount, e.g. no /dev/fuse or no fusermount
+	exitAlreadyMounted     = 7  // Mount path is mounted already
+	exitMountPath          = 8  // Mount path does not exist or is not empty
+	exitNotMounted         = 9  // Unmount of a path which is not mounted
+	exitBusy               = 10 // Unmount of a mount still in use
+	exitStorageUnreachable = 11 // No response from storage
+)
+
+// Causes printed with --output json, one for each exit code
+var exitCauses = map[int]string{
+	exitFailure:            "failure",
+	exitUsage:              "usage",
+	exitConfig:             "config",
+	exitAuth:               "auth",
+	exitContainerMissing:   "container-missing",
+	exitFuseUnavailable:    "fuse-unavailable",
+	exitAlreadyMounted:     "already-mounted",
+	exitMountPath:          "mount-path",
+	exitNotMounted:         "not-mounted",
+	exitBusy:               "busy",
+	exitStorageUnreachable: "storage-unreachable",
+}
+
+// Values of --output of mount and unmount
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Format errors of mount and unmount are printed in
+var errorOutput = outputText
+
+// exitError : Error of a command carrying the code blobfuse2 exits with
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode : Tag the error with the code blobfuse2 exits with, unless an earlier one told its cause already
+func withExitCode(code int, err error) error {
+	if err == nil || exitCode(err) != exitFailure {
+		return err
+	}
+	return exitError{code: code, err: err}
+}
+
+// keepExitCode : Tag the error reporting a cause with the exit code of the cause
+func keepExitCode(cause error, err error) error {
+	if code := exitCode(cause); code != exitFailure {
+		return exitError{code: code, err: err}
+	}
+	return err
+}
+
+// exitCode : Code blobfuse2 exits with for the error a command returned
+func exitCode(err error) int {
+	if err == nil {
+		return exitSuccess
+	}
+
+	var eerr exitError
+	if errors.As(err, &eerr) {
+		return eerr.code
+	}
+
+	// Components wrap the causes they recognise
+	switch {
+	case errors.Is(err, internal.ErrAuthFailed):
+		return exitAuth
+	case errors.Is(err, internal.ErrContainerNotFound):
+		return exitContainerMissing
+	case errors.Is(err, internal.ErrFuseUnavailable):
+		return exitFuseUnavailable
+	case errors.Is(err, internal.ErrStorageUnreachable):
+		return exitStorageUnreachable
+	}
+
+	return exitFailure
+}
+
+// errorReport : Failure of mount or unmount as printed with --output json
+type errorReport struct {
+	ExitCode int    `json:"exitCode"`
+	Cause    string `json:"cause"`
+	Error    string `json:"error"`
+}
+
+// printErrorReport : Print the error as a single line of JSON
+func printErrorReport(w io.Writer, err error) {
+	code := exitCode(err)
+	out, _ := json.Marshal(errorReport{
+		ExitCode: code,
+		Cause:    exitCauses[code],
+		Error:    strings.TrimSpace(strings.TrimPrefix(err.Error(), "Error: ")),
+	})
+	fmt.Fprintln(w, string(out))
+}
+
+// validateErrorOutput : Reject values of --output other than text and json
+func validateErrorOutput() error {
+	if errorOutput != outputText && errorOutput != outputJSON {
+		return withExitCode(exitUsage, fmt.Errorf("invalid output %s, allowed values are %s|%s", errorOutput, outputText, outputJSON))
+	}
+	return nil
+}
+
+// usageArgs : Report arguments the command does not accept as incorrect invocation
+func usageArgs(args cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, a []string) error {
+		return withExitCode(exitUsage, args(cmd, a))
+	}
+}
@@ -0,0 +1,190 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type exitCodeTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *exitCodeTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	options = mountOptions{}
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *exitCodeTestSuite) cleanupTest() {
+	resetCLIFlags(*mountCmd)
+	resetCLIFlags(*unmountCmd)
+	unmountOpts = unmountOptions{}
+	errorOutput = outputText
+}
+
+func TestExitCode(t *testing.T) {
+	suite.Run(t, new(exitCodeTestSuite))
+}
+
+func (suite *exitCodeTestSuite) TestExitCodeOfError() {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "nil", err: nil, code: exitSuccess},
+		{name: "plain", err: errors.New("failed"), code: exitFailure},
+		{name: "auth", err: fmt.Errorf("%w for azstorage", internal.ErrAuthFailed), code: exitAuth},
+		{name: "container", err: fmt.Errorf("%w [data]", internal.ErrContainerNotFound), code: exitContainerMissing},
+		{name: "fuse", err: fmt.Errorf("failed to mount fuse [%w]", internal.ErrFuseUnavailable), code: exitFuseUnavailable},
+		{name: "unreachable", err: fmt.Errorf("%w for azstorage", internal.ErrStorageUnreachable), code: exitStorageUnreachable},
+		{name: "tagged", err: withExitCode(exitConfig, errors.New("invalid config file")), code: exitConfig},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.assert.Equal(tt.code, exitCode(tt.err))
+			if tt.code != exitSuccess {
+				suite.assert.NotEmpty(exitCauses[tt.code])
+			}
+		})
+	}
+}
+
+func (suite *exitCodeTestSuite) TestWithExitCodeKeepsCause() {
+	// A cause told earlier is not overridden by the fallback of the caller
+	err := withExitCode(exitConfig, withExitCode(exitAlreadyMounted, errors.New("directory is already mounted")))
+	suite.assert.Equal(exitAlreadyMounted, exitCode(err))
+	suite.assert.EqualError(err, "directory is already mounted")
+
+	err = withExitCode(exitConfig, fmt.Errorf("%w for azstorage", internal.ErrAuthFailed))
+	suite.assert.Equal(exitAuth, exitCode(err))
+
+	suite.assert.Nil(withExitCode(exitConfig, nil))
+}
+
+func (suite *exitCodeTestSuite) TestKeepExitCode() {
+	cause := fmt.Errorf("%w [data]", internal.ErrContainerNotFound)
+	err := keepExitCode(cause, errors.New("failed to initialize new pipeline [container not found [data]]"))
+	suite.assert.Equal(exitContainerMissing, exitCode(err))
+
+	// Causes not told apart leave the error to the fallback of the caller
+	err = withExitCode(exitConfig, keepExitCode(errors.New("bad option"), errors.New("failed to initialize new pipeline [bad option]")))
+	suite.assert.Equal(exitConfig, exitCode(err))
+}
+
+func (suite *exitCodeTestSuite) TestPrintErrorReport() {
+	var buf bytes.Buffer
+	printErrorReport(&buf, withExitCode(exitAlreadyMounted, errors.New("Error: directory is already mounted\n")))
+
+	var report errorReport
+	suite.assert.Nil(json.Unmarshal(buf.Bytes(), &report))
+	suite.assert.Equal(errorReport{ExitCode: exitAlreadyMounted, Cause: "already-mounted", Error: "directory is already mounted"}, report)
+}
+
+func (suite *exitCodeTestSuite) TestMountPathExitCodes() {
+	defer suite.cleanupTest()
+	dir := suite.T().TempDir()
+	confFile := filepath.Join(dir, "config.yaml")
+	suite.assert.Nil(os.WriteFile(confFile, []byte("logging:\n  type: silent\n"), 0644))
+
+	_, err := executeCommandC(rootCmd, "mount", filepath.Join(dir, "missing"), "--config-file="+confFile)
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitMountPath, exitCode(err))
+
+	// The config file lives in the mount path, which is therefore not empty
+	_, err = executeCommandC(rootCmd, "mount", dir, "--config-file="+confFile)
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitMountPath, exitCode(err))
+}
+
+func (suite *exitCodeTestSuite) TestConfigExitCode() {
+	defer suite.cleanupTest()
+	dir := suite.T().TempDir()
+
+	_, err := executeCommandC(rootCmd, "mount", dir, "--config-file="+filepath.Join(dir, "missing.yaml"))
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitConfig, exitCode(err))
+}
+
+func (suite *exitCodeTestSuite) TestUsageExitCode() {
+	defer suite.cleanupTest()
+
+	_, err := executeCommandC(rootCmd, "mount")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitUsage, exitCode(err))
+
+	_, err = executeCommandC(rootCmd, "mount", suite.T().TempDir(), "--output=yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "invalid output yaml")
+	suite.assert.Equal(exitUsage, exitCode(err))
+
+	_, err = executeCommandC(rootCmd, "unmount", suite.T().TempDir(), "--cleanup-cache", "--preserve-cache")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitUsage, exitCode(err))
+}
+
+func (suite *exitCodeTestSuite) TestUnmountNotMounted() {
+	defer suite.cleanupTest()
+
+	_, err := executeCommandC(rootCmd, "unmount", suite.T().TempDir(), "--output=json")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(outputJSON, errorOutput)
+	suite.assert.Equal(exitNotMounted, exitCode(err))
+}
+
+func (suite *exitCodeTestSuite) TestUnmountExitCode() {
+	dir := suite.T().TempDir()
+	suite.assert.Equal(exitBusy, unmountExitCode(dir, syscall.EBUSY))
+	suite.assert.Equal(exitBusy, unmountExitCode(dir, errors.New("fusermount: failed to unmount "+dir+": Device or resource busy")))
+	suite.assert.Equal(exitNotMounted, unmountExitCode(dir, syscall.EINVAL))
+}
@@ -0,0 +1,266 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+)
+
+// healthProbeOptions : Liveness and readiness of the mount for probes of Kubernetes or a sidecar, served on the
+// health routes of the admin API and written to a file
+type healthProbeOptions struct {
+	File        string `config:"file" yaml:"file,omitempty"`
+	IntervalSec uint32 `config:"interval-sec" yaml:"interval-sec,omitempty"`
+	TimeoutSec  uint32 `config:"timeout-sec" yaml:"timeout-sec,omitempty"`
+}
+
+const (
+	defaultProbeInterval = 30
+	defaultProbeTimeout  = 10
+)
+
+// Checks run this often till the mount is ready, so a new mount gets ready without waiting for a whole interval
+const probeStartupInterval = time.Second
+
+// probeResult : Outcome of the last round of checks
+type probeResult struct {
+	mounted bool
+	live    bool
+	ready   bool
+	reason  string
+	time    time.Time
+}
+
+// healthProbe : Check the mount every interval and answer probes with the outcome of the last round
+type healthProbe struct {
+	opts      healthProbeOptions
+	mountPath string
+	storage   func() error
+	isMounted func(string) bool
+
+	sync.RWMutex
+	result probeResult
+
+	// Checks which did not complete in time are waited for by the next round instead of being started again
+	fsCheck      chan error
+	storageCheck chan error
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// storageChecker : Component which can tell whether storage answers, see azstorage.CheckContainer
+type storageChecker interface {
+	CheckContainer() error
+}
+
+// pipelineStorageCheck : Listing of the container through the storage component of the pipeline, nil without one
+func pipelineStorageCheck(pipeline *internal.Pipeline) func() error {
+	if checker, ok := pipeline.Component("azstorage").(storageChecker); ok {
+		return checker.CheckContainer
+	}
+	return nil
+}
+
+// startHealthProbe : Check the mount in the background, report the outcome on the admin API and write it to the file
+func startHealthProbe(opts healthProbeOptions, mountPath string, storage func() error) *healthProbe {
+	if opts.IntervalSec == 0 {
+		opts.IntervalSec = defaultProbeInterval
+	}
+	if opts.TimeoutSec == 0 {
+		opts.TimeoutSec = defaultProbeTimeout
+	}
+
+	p := &healthProbe{
+		opts:      opts,
+		mountPath: mountPath,
+		storage:   storage,
+		isMounted: common.IsDirectoryMounted,
+		result:    probeResult{live: true, reason: "not checked yet", time: time.Now()},
+		stop:      make(chan struct{}),
+	}
+
+	admin.RegisterProbe(p.report)
+
+	p.done.Add(1)
+	go p.run()
+	return p
+}
+
+// Stop : Stop checking and reporting, the file is removed as the mount is no longer there to be probed
+func (p *healthProbe) Stop() {
+	if p == nil {
+		return
+	}
+
+	admin.RegisterProbe(nil)
+	close(p.stop)
+	p.done.Wait()
+
+	if p.opts.File != "" {
+		_ = os.Remove(p.opts.File)
+	}
+}
+
+// run : Check every interval, every second till the mount got ready
+func (p *healthProbe) run() {
+	defer p.done.Done()
+	interval := time.Duration(p.opts.IntervalSec) * time.Second
+
+	for {
+		result := p.check()
+		p.Lock()
+		if result.live != p.result.live || result.ready != p.result.ready {
+			log.Info("healthProbe : live %t, ready %t %s", result.live, result.ready, result.reason)
+		}
+		p.result = result
+		p.Unlock()
+
+		if p.opts.File != "" {
+			if err := writeProbeFile(p.opts.File, result); err != nil {
+				log.Err("healthProbe : failed to write %s [%s]", p.opts.File, err.Error())
+			}
+		}
+
+		next := interval
+		if !result.mounted {
+			next = probeStartupInterval
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(next):
+		}
+	}
+}
+
+// check : Live while the file system answers, ready when storage answers as well and no component is degraded
+func (p *healthProbe) check() probeResult {
+	timeout := time.Duration(p.opts.TimeoutSec) * time.Second
+	result := probeResult{live: true, time: time.Now()}
+
+	if !p.isMounted(p.mountPath) {
+		result.reason = "not mounted"
+		return result
+	}
+	result.mounted = true
+
+	err := awaitCheck(&p.fsCheck, func() error { return probeFileSystem(p.mountPath, "") }, timeout)
+	if err != nil {
+		result.live = false
+		result.reason = fmt.Sprintf("file system does not answer [%s]", err.Error())
+		return result
+	}
+
+	if p.storage != nil {
+		err = awaitCheck(&p.storageCheck, p.storage, timeout)
+		if err != nil {
+			result.reason = fmt.Sprintf("storage does not answer [%s]", err.Error())
+			return result
+		}
+	}
+
+	// Components tell on their own when they are not working as they should, e.g. the file cache out of disk
+	status := admin.Dispatch(admin.Request{Verb: admin.VerbStatus}).Status
+	components := make([]string, 0, len(status))
+	for component := range status {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		if status[component][admin.StatusHealth] == admin.HealthDegraded {
+			result.reason = fmt.Sprintf("%s is degraded [%s]", component, status[component][admin.StatusReason])
+			return result
+		}
+	}
+
+	result.ready = true
+	return result
+}
+
+// awaitCheck : Run the check, or wait for the one still pending, for at most the timeout
+func awaitCheck(pending *chan error, check func() error, timeout time.Duration) error {
+	if *pending == nil {
+		result := make(chan error, 1)
+		go func() {
+			result <- check()
+		}()
+		*pending = result
+	}
+
+	select {
+	case err := <-*pending:
+		*pending = nil
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("no answer within %s", timeout)
+	}
+}
+
+// report : Liveness and readiness from the last round of checks, see admin.RouteHealth and admin.RouteLive
+func (p *healthProbe) report() (bool, bool, string) {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.result.live, p.result.ready, p.result.reason
+}
+
+// writeProbeFile : Replace the file with the outcome of the last round, one key=value per line for exec probes to grep
+func writeProbeFile(path string, result probeResult) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("live=%t\nready=%t\nreason=%s\ntime=%s\n",
+		result.live, result.ready, result.reason, result.time.UTC().Format(time.RFC3339))
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, []byte(content), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,165 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type healthProbeTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *healthProbeTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func TestHealthProbe(t *testing.T) {
+	suite.Run(t, new(healthProbeTestSuite))
+}
+
+// newTestProbe : Probe of a mount point which is taken for mounted, checked in the test instead of in the background
+func (suite *healthProbeTestSuite) newTestProbe(storage func() error) *healthProbe {
+	return &healthProbe{
+		opts:      healthProbeOptions{IntervalSec: 1, TimeoutSec: 1},
+		mountPath: suite.T().TempDir(),
+		storage:   storage,
+		isMounted: func(string) bool { return true },
+	}
+}
+
+func (suite *healthProbeTestSuite) TestNotMounted() {
+	p := suite.newTestProbe(nil)
+	p.isMounted = func(string) bool { return false }
+
+	result := p.check()
+	suite.assert.False(result.mounted)
+	suite.assert.True(result.live)
+	suite.assert.False(result.ready)
+	suite.assert.Equal("not mounted", result.reason)
+}
+
+func (suite *healthProbeTestSuite) TestReady() {
+	p := suite.newTestProbe(func() error { return nil })
+
+	result := p.check()
+	suite.assert.True(result.live)
+	suite.assert.True(result.ready)
+	suite.assert.Empty(result.reason)
+}
+
+func (suite *healthProbeTestSuite) TestStorageDown() {
+	p := suite.newTestProbe(func() error { return errors.New("dial tcp: no such host") })
+
+	result := p.check()
+	suite.assert.True(result.live)
+	suite.assert.False(result.ready)
+	suite.assert.Contains(result.reason, "storage does not answer [dial tcp: no such host]")
+}
+
+func (suite *healthProbeTestSuite) TestStorageHangs() {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	p := suite.newTestProbe(func() error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	result := p.check()
+	suite.assert.False(result.ready)
+	suite.assert.Contains(result.reason, "no answer within 1s")
+
+	// The check still pending is waited for again instead of piling up another one
+	_ = p.check()
+	suite.assert.EqualValues(1, calls.Load())
+}
+
+func (suite *healthProbeTestSuite) TestDegradedComponent() {
+	admin.RegisterStatus("probe_test", func() map[string]string {
+		return map[string]string{admin.StatusHealth: admin.HealthDegraded, admin.StatusReason: "disk full"}
+	})
+	defer admin.UnregisterStatus("probe_test")
+	p := suite.newTestProbe(nil)
+
+	result := p.check()
+	suite.assert.True(result.live)
+	suite.assert.False(result.ready)
+	suite.assert.Equal("probe_test is degraded [disk full]", result.reason)
+}
+
+func (suite *healthProbeTestSuite) TestReport() {
+	p := suite.newTestProbe(nil)
+	p.result = probeResult{live: true, ready: false, reason: "storage does not answer [timeout]"}
+
+	live, ready, reason := p.report()
+	suite.assert.True(live)
+	suite.assert.False(ready)
+	suite.assert.Equal("storage does not answer [timeout]", reason)
+}
+
+func (suite *healthProbeTestSuite) TestProbeFile() {
+	file := filepath.Join(suite.T().TempDir(), "run", "probe")
+
+	// The mount path is no mount point, so the probe reports it as not mounted
+	p := startHealthProbe(healthProbeOptions{File: file}, suite.T().TempDir(), nil)
+
+	suite.assert.Eventually(func() bool {
+		data, err := os.ReadFile(file)
+		return err == nil && strings.Contains(string(data), "live=true\nready=false\nreason=not mounted\n")
+	}, 5*time.Second, 50*time.Millisecond)
+
+	p.Stop()
+	_, err := os.Stat(file)
+	suite.assert.True(os.IsNotExist(err))
+}
@@ -0,0 +1,211 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+)
+
+type lsOptions struct {
+	ConfigFile string
+	Long       bool
+	Recursive  bool
+	Output     string
+}
+
+var lsOpts lsOptions
+
+// storageLister : Part of the azstorage component listing needs, so tests can stand in for storage
+type storageLister interface {
+	GetAttr(options internal.GetAttrOptions) (*internal.ObjAttr, error)
+	StreamDir(options internal.StreamDirOptions) ([]*internal.ObjAttr, string, error)
+}
+
+// lsEntry : Entry of a listing as printed with --output=json
+type lsEntry struct {
+	Path  string `json:"path"`
+	Dir   bool   `json:"dir"`
+	Size  int64  `json:"size"`
+	Mode  string `json:"mode"`
+	Mtime string `json:"mtime"`
+}
+
+var lsCmd = &cobra.Command{
+	Use:               "ls [path]",
+	Short:             "List files and directories in the container without mounting it",
+	Long:              "List files and directories in the container, or under the given path, straight from storage with the credentials of the config file. Nothing is mounted, so the data can be looked at before mounting it.",
+	SuggestFor:        []string{"list", "dir"},
+	Example:           "blobfuse2 ls --config-file=config.yaml\nblobfuse2 ls datasets --long --recursive --config-file=config.yaml",
+	Args:              cobra.MaximumNArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if lsOpts.Output != "table" && lsOpts.Output != "json" {
+			return fmt.Errorf("invalid output format %s, shall be table or json", lsOpts.Output)
+		}
+
+		name := ""
+		if len(args) > 0 {
+			name = cleanStoragePath(args[0])
+		}
+
+		azComponent, err := getAzStorageComponent(lsOpts.ConfigFile)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = azComponent.Stop() }()
+
+		return listStorage(cmd.OutOrStdout(), azComponent, name)
+	},
+}
+
+// cleanStoragePath : Path in the container as given on the command line, without leading or trailing slashes
+func cleanStoragePath(path string) string {
+	return strings.Trim(strings.TrimPrefix(path, "."), "/")
+}
+
+// listStorage : Print the file at the path, or the entries of the directory at the path
+func listStorage(out io.Writer, lister storageLister, name string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	emit := func(attr *internal.ObjAttr) error {
+		return printLsEntry(w, out, attr)
+	}
+
+	if name != "" {
+		attr, err := lister.GetAttr(internal.GetAttrOptions{Name: name})
+		if err != nil {
+			return fmt.Errorf("failed to get attributes of %s [%s]", name, err.Error())
+		}
+
+		if !attr.IsDir() {
+			if err = emit(attr); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+	}
+
+	err := walkStorage(lister, name, lsOpts.Recursive, emit)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// printLsEntry : One entry of the listing, in the chosen format
+func printLsEntry(w *tabwriter.Writer, out io.Writer, attr *internal.ObjAttr) error {
+	path := attr.Path
+	if !lsOpts.Recursive && attr.Name != "" {
+		path = attr.Name
+	}
+
+	if lsOpts.Output == "json" {
+		return json.NewEncoder(out).Encode(lsEntry{
+			Path:  attr.Path,
+			Dir:   attr.IsDir(),
+			Size:  attr.Size,
+			Mode:  storageMode(attr).String(),
+			Mtime: attr.Mtime.Format(time.RFC3339),
+		})
+	}
+
+	if attr.IsDir() {
+		path += "/"
+	}
+
+	if !lsOpts.Long {
+		_, err := fmt.Fprintln(w, path)
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", storageMode(attr), attr.Size, attr.Mtime.Format("2006-01-02 15:04:05"), path)
+	return err
+}
+
+// storageMode : Mode of the entry along with its type
+func storageMode(attr *internal.ObjAttr) os.FileMode {
+	if attr.IsDir() {
+		return attr.Mode | os.ModeDir
+	}
+	return attr.Mode
+}
+
+// walkStorage : Call fn for every entry of the directory, page by page, and for everything under it when recursive
+func walkStorage(lister storageLister, name string, recursive bool, fn func(attr *internal.ObjAttr) error) error {
+	token := ""
+	for {
+		entries, next, err := lister.StreamDir(internal.StreamDirOptions{Name: name, Token: token})
+		if err != nil {
+			return fmt.Errorf("failed to list %s [%s]", name, err.Error())
+		}
+
+		for _, attr := range entries {
+			if err = fn(attr); err != nil {
+				return err
+			}
+
+			if recursive && attr.IsDir() {
+				if err = walkStorage(lister, attr.Path, true, fn); err != nil {
+					return err
+				}
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+		token = next
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(lsCmd)
+
+	lsCmd.Flags().StringVar(&lsOpts.ConfigFile, "config-file", "",
+		"Configures the path for the file where the account credentials are provided. Default is config.yaml in current directory.")
+	_ = lsCmd.MarkFlagFilename("config-file", "yaml")
+
+	lsCmd.Flags().BoolVarP(&lsOpts.Long, "long", "l", false, "Show mode, size and last modified time of each entry")
+	lsCmd.Flags().BoolVarP(&lsOpts.Recursive, "recursive", "R", false, "List everything under the directories as well")
+	lsCmd.Flags().StringVar(&lsOpts.Output, "output", "table", "Output format, table or json (one object per line)")
+}
@@ -0,0 +1,221 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeLister : Container held in memory, listed two entries per page
+type fakeLister struct {
+	attrs map[string]*internal.ObjAttr
+	dirs  map[string][]string // directory -> paths of its entries in order
+}
+
+func newFakeLister() *fakeLister {
+	return &fakeLister{attrs: make(map[string]*internal.ObjAttr), dirs: make(map[string][]string)}
+}
+
+func (f *fakeLister) add(path string, dir bool, size int64) {
+	name, parent := path, ""
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		parent, name = path[:i], path[i+1:]
+	}
+
+	attr := &internal.ObjAttr{Path: path, Name: name, Size: size, Mode: 0644, Mtime: time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)}
+	if dir {
+		attr.Flags.Set(internal.PropFlagIsDir)
+		attr.Mode = 0755
+	}
+	f.attrs[path] = attr
+	f.dirs[parent] = append(f.dirs[parent], path)
+}
+
+func (f *fakeLister) GetAttr(options internal.GetAttrOptions) (*internal.ObjAttr, error) {
+	attr, found := f.attrs[options.Name]
+	if !found {
+		return nil, syscall.ENOENT
+	}
+	return attr, nil
+}
+
+func (f *fakeLister) StreamDir(options internal.StreamDirOptions) ([]*internal.ObjAttr, string, error) {
+	start := 0
+	if options.Token != "" {
+		start, _ = strconv.Atoi(options.Token)
+	}
+
+	paths := f.dirs[options.Name]
+	end := start + 2
+	next := strconv.Itoa(end)
+	if end >= len(paths) {
+		end, next = len(paths), ""
+	}
+
+	entries := make([]*internal.ObjAttr, 0, end-start)
+	for _, path := range paths[start:end] {
+		entries = append(entries, f.attrs[path])
+	}
+	return entries, next, nil
+}
+
+// testContainer : a, dir/{b, c, sub/d}, e
+func testContainer() *fakeLister {
+	f := newFakeLister()
+	f.add("a", false, 10)
+	f.add("dir", true, 0)
+	f.add("dir/b", false, 1024)
+	f.add("dir/c", false, 2048)
+	f.add("dir/sub", true, 0)
+	f.add("dir/sub/d", false, 4096)
+	f.add("e", false, 5)
+	return f
+}
+
+type lsCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *lsCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *lsCmdTestSuite) cleanupTest() {
+	lsOpts = lsOptions{Output: "table"}
+	lsCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestLsCommand(t *testing.T) {
+	suite.Run(t, new(lsCmdTestSuite))
+}
+
+func (suite *lsCmdTestSuite) TestLsInvalidOutput() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "ls", "--output=xml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid output format")
+}
+
+func (suite *lsCmdTestSuite) TestLsInvalidConfigFile() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "ls", "--config-file=cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid config file")
+}
+
+func (suite *lsCmdTestSuite) TestCleanStoragePath() {
+	suite.assert.Equal("", cleanStoragePath("/"))
+	suite.assert.Equal("", cleanStoragePath("."))
+	suite.assert.Equal("dir/sub", cleanStoragePath("./dir/sub/"))
+	suite.assert.Equal("dir", cleanStoragePath("/dir"))
+}
+
+func (suite *lsCmdTestSuite) TestLs() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	out := &bytes.Buffer{}
+
+	err := listStorage(out, testContainer(), "")
+	suite.assert.Nil(err)
+	suite.assert.Equal("a\ndir/\ne\n", out.String())
+
+	out.Reset()
+	err = listStorage(out, testContainer(), "dir")
+	suite.assert.Nil(err)
+	suite.assert.Equal("b\nc\nsub/\n", out.String())
+
+	out.Reset()
+	err = listStorage(out, testContainer(), "dir/c")
+	suite.assert.Nil(err)
+	suite.assert.Equal("c\n", out.String())
+
+	err = listStorage(out, testContainer(), "missing")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "failed to get attributes of missing")
+}
+
+func (suite *lsCmdTestSuite) TestLsRecursiveLong() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	lsOpts.Recursive, lsOpts.Long = true, true
+	out := &bytes.Buffer{}
+
+	err := listStorage(out, testContainer(), "dir")
+	suite.assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	suite.assert.Len(lines, 4)
+	suite.assert.Equal([]string{"-rw-r--r--", "1024", "2026-10-18", "10:00:00", "dir/b"}, strings.Fields(lines[0]))
+	suite.assert.Equal([]string{"drwxr-xr-x", "0", "2026-10-18", "10:00:00", "dir/sub/"}, strings.Fields(lines[2]))
+	suite.assert.Equal("dir/sub/d", strings.Fields(lines[3])[4])
+}
+
+func (suite *lsCmdTestSuite) TestLsJSON() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	lsOpts.Output = "json"
+	out := &bytes.Buffer{}
+
+	err := listStorage(out, testContainer(), "")
+	suite.assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	suite.assert.Len(lines, 3)
+	entry := lsEntry{}
+	err = json.Unmarshal([]byte(lines[1]), &entry)
+	suite.assert.Nil(err)
+	suite.assert.Equal(lsEntry{Path: "dir", Dir: true, Mode: "drwxr-xr-x", Mtime: "2026-10-18T10:00:00Z"}, entry)
+}
@@ -54,10 +54,14 @@ import (
 	"github.com/Azure/azure-storage-fuse/v2/common"
 	"github.com/Azure/azure-storage-fuse/v2/common/config"
 	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/common/tracing"
+	"github.com/Azure/azure-storage-fuse/v2/component/libfuse"
 	"github.com/Azure/azure-storage-fuse/v2/internal"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
 
 	"github.com/sevlyar/go-daemon"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 type LogOptions struct {
@@ -67,26 +71,55 @@ type LogOptions struct {
 	MaxLogFileSize uint64 `config:"max-file-size-mb" yaml:"max-file-size-mb,omitempty"`
 	LogFileCount   uint64 `config:"file-count" yaml:"file-count,omitempty"`
 	TimeTracker    bool   `config:"track-time" yaml:"track-time,omitempty"`
+	Format         string `config:"format" yaml:"format,omitempty"`
+}
+
+// Log file path under which the base logger writes to stdout
+const logToStdout = "stdout"
+
+// logFilePath : Expanded path of the log file, stdout is not a file and kept as it is
+func logFilePath(path string) string {
+	if path == logToStdout {
+		return path
+	}
+	return common.ExpandPath(path)
+}
+
+// adminAPIOptions : Opt-in HTTP endpoint serving the admin verbs, see admin.StartHTTP
+type adminAPIOptions struct {
+	Address string `config:"address" yaml:"address,omitempty"`
+	Token   string `config:"token" yaml:"token,omitempty"`
 }
 
 type mountOptions struct {
 	MountPath  string
 	ConfigFile string
 
-	Logging           LogOptions     `config:"logging"`
-	Components        []string       `config:"components"`
-	Foreground        bool           `config:"foreground"`
-	NonEmpty          bool           `config:"nonempty"`
-	DefaultWorkingDir string         `config:"default-working-dir"`
-	CPUProfile        string         `config:"cpu-profile"`
-	MemProfile        string         `config:"mem-profile"`
-	PassPhrase        string         `config:"passphrase"`
-	SecureConfig      bool           `config:"secure-config"`
-	DynamicProfiler   bool           `config:"dynamic-profile"`
-	ProfilerPort      int            `config:"profiler-port"`
-	ProfilerIP        string         `config:"profiler-ip"`
-	MonitorOpt        monitorOptions `config:"health_monitor"`
-	WaitForMount      time.Duration  `config:"wait-for-mount"`
+	Logging            LogOptions            `config:"logging"`
+	Components         []string              `config:"components"`
+	Foreground         bool                  `config:"foreground"`
+	NonEmpty           bool                  `config:"nonempty"`
+	DefaultWorkingDir  string                `config:"default-working-dir"`
+	CPUProfile         string                `config:"cpu-profile"`
+	MemProfile         string                `config:"mem-profile"`
+	PassPhrase         string                `config:"passphrase"`
+	PassphraseSource   string                `config:"passphrase-source"`
+	EnvFile            string                `config:"env-file"`
+	SecureConfig       bool                  `config:"secure-config"`
+	DynamicProfiler    bool                  `config:"dynamic-profile"`
+	ProfilerPort       int                   `config:"profiler-port"`
+	ProfilerIP         string                `config:"profiler-ip"`
+	MonitorOpt         monitorOptions        `config:"health_monitor"`
+	WaitForMount       time.Duration         `config:"wait-for-mount"`
+	AdminAPI           adminAPIOptions       `config:"admin-api"`
+	Supervisor         supervisorOptions     `config:"supervisor"`
+	Preset             string                `config:"preset"`
+	ResourceLimits     resourceLimitsOptions `config:"resource-limits"`
+	ShutdownTimeoutSec uint32                `config:"shutdown-timeout-sec"`
+	ShutdownCache      string                `config:"shutdown-cache"`
+	HealthProbe        healthProbeOptions    `config:"health-probe"`
+	Tracing            tracing.Options       `config:"tracing"`
+	DryRun             bool                  `config:"dry-run"`
 
 	// v1 support
 	Streaming      bool     `config:"streaming"`
@@ -101,18 +134,33 @@ func (opt *mountOptions) validate(skipEmptyMount bool) error {
 		return fmt.Errorf("mount path not provided")
 	}
 
-	if _, err := os.Stat(opt.MountPath); os.IsNotExist(err) {
-		return fmt.Errorf("mount directory does not exists")
-	} else if common.IsDirectoryMounted(opt.MountPath) {
-		return fmt.Errorf("directory is already mounted")
-	} else if !skipEmptyMount && !common.IsDirectoryEmpty(opt.MountPath) {
-		return fmt.Errorf("mount directory is not empty")
+	// Mount point of a mount being taken over is in use, and is not served till this process serves it
+	if !libfuse.TakingOver() {
+		if err := validateMountPath(opt.MountPath, skipEmptyMount); err != nil {
+			return err
+		}
 	}
 
 	if err := common.ELogLevel.Parse(opt.Logging.LogLevel); err != nil {
 		return fmt.Errorf("invalid log level [%s]", err.Error())
 	}
 
+	if err := opt.ResourceLimits.validate(); err != nil {
+		return err
+	}
+
+	if opt.Logging.Format != "" && opt.Logging.Format != "text" && opt.Logging.Format != "json" {
+		return fmt.Errorf("invalid log format %s, allowed values are text|json", opt.Logging.Format)
+	}
+
+	if opt.ShutdownCache != "" && opt.ShutdownCache != admin.CachePreserve && opt.ShutdownCache != admin.CacheCleanup {
+		return fmt.Errorf("invalid shutdown-cache %s, allowed values are %s|%s", opt.ShutdownCache, admin.CachePreserve, admin.CacheCleanup)
+	}
+
+	if err := opt.Tracing.Validate(); err != nil {
+		return err
+	}
+
 	if opt.DefaultWorkingDir != "" {
 		common.DefaultWorkDir = opt.DefaultWorkingDir
 
@@ -137,8 +185,8 @@ func (opt *mountOptions) validate(skipEmptyMount bool) error {
 		}
 	}
 
-	opt.Logging.LogFilePath = common.ExpandPath(opt.Logging.LogFilePath)
-	if !common.DirectoryExists(filepath.Dir(opt.Logging.LogFilePath)) {
+	opt.Logging.LogFilePath = logFilePath(opt.Logging.LogFilePath)
+	if opt.Logging.LogFilePath != logToStdout && !common.DirectoryExists(filepath.Dir(opt.Logging.LogFilePath)) {
 		err := os.MkdirAll(filepath.Dir(opt.Logging.LogFilePath), os.FileMode(0776)|os.ModeDir)
 		if err != nil {
 			return fmt.Errorf("invalid log file path [%s]", err.Error())
@@ -158,21 +206,24 @@ func (opt *mountOptions) validate(skipEmptyMount bool) error {
 }
 
 func OnConfigChange() {
-	newLogOptions := &LogOptions{}
-	err := config.UnmarshalKey("logging", newLogOptions)
+	// Options left out of the config file keep the values the mount resolved for them
+	newLogOptions := options.Logging
+	err := config.UnmarshalKey("logging", &newLogOptions)
 	if err != nil {
 		log.Err("Mount::OnConfigChange : Invalid logging options [%s]", err.Error())
+		return
 	}
 
 	var logLevel common.LogLevel
 	err = logLevel.Parse(newLogOptions.LogLevel)
 	if err != nil {
 		log.Err("Mount::OnConfigChange : Invalid log level [%s]", newLogOptions.LogLevel)
+		return
 	}
 
 	err = log.SetConfig(common.LogConfig{
 		Level:       logLevel,
-		FilePath:    common.ExpandPath(newLogOptions.LogFilePath),
+		FilePath:    logFilePath(newLogOptions.LogFilePath),
 		MaxFileSize: newLogOptions.MaxLogFileSize,
 		FileCount:   newLogOptions.LogFileCount,
 		TimeTracker: newLogOptions.TimeTracker,
@@ -183,6 +234,16 @@ func OnConfigChange() {
 	}
 }
 
+// reloadConfig : Read the config file again, components apply what they can change while mounted
+func reloadConfig(_ admin.Request) (string, error) {
+	err := config.Reload()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("config reloaded from %s", options.ConfigFile), nil
+}
+
 // parseConfig : Based on config file or encrypted data parse the provided config
 func parseConfig() error {
 	options.ConfigFile = common.ExpandPath(options.ConfigFile)
@@ -192,12 +253,14 @@ func parseConfig() error {
 		filepath.Ext(options.ConfigFile) == SecureConfigExtension {
 
 		// Validate config is to be secured on write or not
-		if options.PassPhrase == "" {
-			options.PassPhrase = os.Getenv(SecureConfigEnvName)
+		passphrase, err := lookupPassphrase(options.PassPhrase, options.PassphraseSource)
+		if err != nil {
+			return err
 		}
+		options.PassPhrase = passphrase
 
 		if options.PassPhrase == "" {
-			return fmt.Errorf("no passphrase provided to decrypt the config file.\n Either use --passphrase cli option or store passphrase in BLOBFUSE2_SECURE_CONFIG_PASSPHRASE environment variable")
+			return fmt.Errorf("no passphrase provided to decrypt the config file.\n Either use --passphrase or --passphrase-source cli option or store passphrase in BLOBFUSE2_SECURE_CONFIG_PASSPHRASE environment variable")
 		}
 
 		cipherText, err := os.ReadFile(options.ConfigFile)
@@ -232,12 +295,23 @@ var mountCmd = &cobra.Command{
 	Short:             "Mounts the azure container as a filesystem",
 	Long:              "Mounts the azure container as a filesystem",
 	SuggestFor:        []string{"mnt", "mout"},
-	Args:              cobra.ExactArgs(1),
+	Args:              usageArgs(cobra.ExactArgs(1)),
 	FlagErrorHandling: cobra.ExitOnError,
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateErrorOutput(); err != nil {
+			return err
+		}
+
 		options.MountPath = common.ExpandPath(args[0])
 		configFileExists := true
 
+		if options.EnvFile != "" {
+			err := loadEnvFile(common.ExpandPath(options.EnvFile))
+			if err != nil {
+				return withExitCode(exitConfig, fmt.Errorf("failed to load env file %s [%s]", options.EnvFile, err.Error()))
+			}
+		}
+
 		if options.ConfigFile == "" {
 			// Config file is not set in cli parameters
 			// Blobfuse2 defaults to config.yaml in current directory
@@ -254,18 +328,38 @@ var mountCmd = &cobra.Command{
 		if configFileExists {
 			err := parseConfig()
 			if err != nil {
-				return err
+				return withExitCode(exitConfig, err)
 			}
 		}
 
 		err := config.Unmarshal(&options)
 		if err != nil {
-			return fmt.Errorf("failed to unmarshal config [%s]", err.Error())
+			return withExitCode(exitConfig, fmt.Errorf("failed to unmarshal config [%s]", err.Error()))
+		}
+
+		if dryRunAzurite != "" && !options.DryRun {
+			return withExitCode(exitUsage, errors.New("--azurite is only used with --dry-run, to mount Azurite configure its account"))
+		}
+
+		err = applyPreset(options.Preset)
+		if err != nil {
+			return withExitCode(exitConfig, err)
+		}
+
+		if !snapshotAt.IsZero() {
+			applySnapshotMode(snapshotAt)
 		}
 
-		if !configFileExists || len(options.Components) == 0 {
+		if len(options.Components) == 0 {
 			pipeline := []string{"libfuse"}
 
+			// Operations are timed as they come from the kernel, before any cache answers them
+			metricsEnabled := false
+			_ = config.UnmarshalKey("metrics.enabled", &metricsEnabled)
+			if metricsEnabled {
+				pipeline = append(pipeline, "metrics")
+			}
+
 			if config.IsSet("streaming") && options.Streaming {
 				pipeline = append(pipeline, "stream")
 			} else {
@@ -288,7 +382,7 @@ var mountCmd = &cobra.Command{
 			for _, v := range options.LibfuseOptions {
 				parameter := strings.Split(v, "=")
 				if len(parameter) > 2 || len(parameter) <= 0 {
-					return errors.New(common.FuseAllowedFlags)
+					return withExitCode(exitUsage, errors.New(common.FuseAllowedFlags))
 				}
 
 				v = strings.TrimSpace(v)
@@ -313,47 +407,66 @@ var mountCmd = &cobra.Command{
 				} else if strings.HasPrefix(v, "umask=") {
 					permission, err := strconv.ParseUint(parameter[1], 10, 32)
 					if err != nil {
-						return fmt.Errorf("failed to parse umask [%s]", err.Error())
+						return withExitCode(exitUsage, fmt.Errorf("failed to parse umask [%s]", err.Error()))
 					}
 					perm := ^uint32(permission) & 777
 					config.Set("libfuse.default-permission", fmt.Sprint(perm))
 				} else if strings.HasPrefix(v, "uid=") {
 					val, err := strconv.ParseUint(parameter[1], 10, 32)
 					if err != nil {
-						return fmt.Errorf("failed to parse uid [%s]", err.Error())
+						return withExitCode(exitUsage, fmt.Errorf("failed to parse uid [%s]", err.Error()))
 					}
 					config.Set("libfuse.uid", fmt.Sprint(val))
 				} else if strings.HasPrefix(v, "gid=") {
 					val, err := strconv.ParseUint(parameter[1], 10, 32)
 					if err != nil {
-						return fmt.Errorf("failed to parse gid [%s]", err.Error())
+						return withExitCode(exitUsage, fmt.Errorf("failed to parse gid [%s]", err.Error()))
 					}
 					config.Set("libfuse.gid", fmt.Sprint(val))
 				} else if v == "direct_io" || v == "direct_io=true" {
 					config.Set("libfuse.direct-io", "true")
 				} else {
-					return errors.New(common.FuseAllowedFlags)
+					return withExitCode(exitUsage, errors.New(common.FuseAllowedFlags))
 				}
 			}
 		}
 
+		// Modes the kernel enforces shall reflect the ACLs of HNS accounts, unless told otherwise
+		if config.IsSet("libfuse.enforce-permissions") && !config.IsSet("azstorage.honour-acl") {
+			enforcePermissions := false
+			_ = config.UnmarshalKey("libfuse.enforce-permissions", &enforcePermissions)
+			if enforcePermissions {
+				config.Set("azstorage.honour-acl", "true")
+			}
+		}
+
 		if !config.IsSet("logging.file-path") {
 			options.Logging.LogFilePath = common.DefaultLogFilePath
 		}
 
+		// A mount in foreground logging JSON is run by a container runtime or service manager, which reads stdout
+		if options.Foreground && options.Logging.Format == "json" {
+			if !config.IsSet("logging.type") {
+				options.Logging.Type = "base"
+			}
+			if !config.IsSet("logging.file-path") {
+				options.Logging.LogFilePath = logToStdout
+			}
+		}
+
 		if !config.IsSet("logging.level") {
 			options.Logging.LogLevel = "LOG_WARNING"
 		}
 
 		err = options.validate(options.NonEmpty || skipNonEmpty)
 		if err != nil {
-			return err
+			return withExitCode(exitConfig, err)
 		}
 
 		var logLevel common.LogLevel
 		err = logLevel.Parse(options.Logging.LogLevel)
 		if err != nil {
-			return fmt.Errorf("invalid log level [%s]", err.Error())
+			return withExitCode(exitConfig, fmt.Errorf("invalid log level [%s]", err.Error()))
 		}
 
 		err = log.SetDefaultLogger(options.Logging.Type, common.LogConfig{
@@ -362,13 +475,15 @@ var mountCmd = &cobra.Command{
 			FileCount:   options.Logging.LogFileCount,
 			Level:       logLevel,
 			TimeTracker: options.Logging.TimeTracker,
+			Format:      options.Logging.Format,
 		})
 
 		if err != nil {
 			return fmt.Errorf("failed to initialize logger [%s]", err.Error())
 		}
 
-		if !disableVersionCheck {
+		// A process taking over a mount holds up its operations till it serves them, so it gets to that first
+		if !disableVersionCheck && !options.DryRun && !libfuse.TakingOver() {
 			err := VersionCheck()
 			if err != nil {
 				log.Err(err.Error())
@@ -397,90 +512,54 @@ var mountCmd = &cobra.Command{
 
 		config.Set("mount-path", options.MountPath)
 
+		if options.DryRun {
+			return dryRunMount(cmd.OutOrStdout())
+		}
+
+		// The cgroup of the mount is a child of the one this process was started in, controllers are enabled for it
+		// once this process left its parent. Processes started for the mount below, in background or by the supervisor,
+		// are then started in it.
+		if _, err := placeInCgroup(options.ResourceLimits, options.MountPath); err != nil {
+			return withExitCode(exitConfig, err)
+		}
+
+		// The supervisor runs the mount in a child of its own, which it tells apart through the environment
+		if _, supervised := supervisedRestarts(); supervised {
+			options.Foreground = true
+		} else if libfuse.TakingOver() {
+			prepareTakeover()
+		} else if options.Supervisor.Enabled {
+			common.ForegroundMount = options.Foreground
+			if !options.Foreground {
+				return mountInBackground(superviseMount)
+			}
+			return superviseMount(context.Background())
+		}
+
 		var pipeline *internal.Pipeline
 
 		log.Crit("Starting Blobfuse2 Mount : %s on [%s]", common.Blobfuse2Version, common.GetCurrentDistro())
 		log.Crit("Logging level set to : %s", logLevel.String())
+		if options.Preset != "" {
+			log.Crit("Workload preset : %s", options.Preset)
+		}
+		if !snapshotAt.IsZero() {
+			log.Crit("Read-only snapshot as of : %s", snapshotAt.Format(time.RFC3339))
+		}
 		pipeline, err = internal.NewPipeline(options.Components, !daemon.WasReborn())
 		if err != nil {
 			log.Err("mount : failed to initialize new pipeline [%v]", err)
-			return Destroy(fmt.Sprintf("failed to initialize new pipeline [%s]", err.Error()))
+			// Components fail to configure mostly on invalid config, unless they told the cause
+			return withExitCode(exitConfig, keepExitCode(err, Destroy(fmt.Sprintf("failed to initialize new pipeline [%s]", err.Error()))))
 		}
 
 		common.ForegroundMount = options.Foreground
 
 		log.Info("mount: Mounting blobfuse2 on %s", options.MountPath)
 		if !options.Foreground {
-			pidFile := strings.Replace(options.MountPath, "/", "_", -1) + ".pid"
-			pidFileName := filepath.Join(os.ExpandEnv(common.DefaultWorkDir), pidFile)
-
-			pid := os.Getpid()
-			fname := fmt.Sprintf("/tmp/blobfuse2.%v", pid)
-
-			dmnCtx := &daemon.Context{
-				PidFileName: pidFileName,
-				PidFilePerm: 0644,
-				Umask:       022,
-				LogFileName: fname, // this will redirect stderr of child to given file
-			}
-
-			ctx, _ := context.WithCancel(context.Background()) //nolint
-
-			// Signal handlers for parent and child to communicate success or failures in mount
-			var sigusr2, sigchild chan os.Signal
-			if !daemon.WasReborn() { // execute in parent only
-				sigusr2 = make(chan os.Signal, 1)
-				signal.Notify(sigusr2, syscall.SIGUSR2)
-
-				sigchild = make(chan os.Signal, 1)
-				signal.Notify(sigchild, syscall.SIGCHLD)
-			} else { // execute in child only
-				daemon.SetSigHandler(sigusrHandler(pipeline, ctx), syscall.SIGUSR1, syscall.SIGUSR2)
-				go func() {
-					_ = daemon.ServeSignals()
-				}()
-			}
-
-			child, err := dmnCtx.Reborn()
-			if err != nil {
-				log.Err("mount : failed to daemonize application [%v]", err)
-				return Destroy(fmt.Sprintf("failed to daemonize application [%s]", err.Error()))
-			}
-
-			log.Debug("mount: foreground disabled, child = %v", daemon.WasReborn())
-			if child == nil { // execute in child only
-				defer dmnCtx.Release() // nolint
-				setGOConfig()
-				go startDynamicProfiler()
-
-				// In case of failure stderr will have the error emitted by child and parent will read
-				// those logs from the file set in daemon context
+			return mountInBackground(func(ctx context.Context) error {
 				return runPipeline(pipeline, ctx)
-			} else { // execute in parent only
-				defer os.Remove(fname)
-
-				select {
-				case <-sigusr2:
-					log.Info("mount: Child [%v] mounted successfully at %s", child.Pid, options.MountPath)
-
-				case <-sigchild:
-					// Get error string from the child, stderr or child was redirected to a file
-					log.Info("mount: Child [%v] terminated from %s", child.Pid, options.MountPath)
-
-					buff, err := os.ReadFile(dmnCtx.LogFileName)
-					if err != nil {
-						log.Err("mount: failed to read child [%v] failure logs [%s]", child.Pid, err.Error())
-						return Destroy(fmt.Sprintf("failed to mount, please check logs [%s]", err.Error()))
-					} else {
-						return Destroy(string(buff))
-					}
-
-				case <-time.After(options.WaitForMount):
-					log.Info("mount: Child [%v : %s] status check timeout", child.Pid, options.MountPath)
-				}
-
-				_ = log.Destroy()
-			}
+			})
 		} else {
 			if options.CPUProfile != "" {
 				os.Remove(options.CPUProfile)
@@ -540,14 +619,104 @@ func runPipeline(pipeline *internal.Pipeline, ctx context.Context) error {
 	common.PollingPipe += "_" + pid
 	log.Debug("Mount::runPipeline : blobfuse2 pid = %v, transfer pipe = %v, polling pipe = %v", pid, common.TransferPipe, common.PollingPipe)
 
+	// Ceilings are applied to the process serving the mount, before its components allocate buffers
+	err := applyResourceLimits(options.ResourceLimits, options.MountPath)
+	if err != nil {
+		log.Err("Mount::runPipeline : %s", err.Error())
+		return Destroy(err.Error())
+	}
+
 	go startMonitor(os.Getpid())
+	reportSupervisorIncident()
+
+	// SIGTERM and SIGINT flush the pipeline and unmount, so it is stopped below like on a regular unmount
+	stopShutdown := handleShutdown(options.MountPath)
+	defer stopShutdown()
+
+	// Commands like 'cache invalidate' reach the mount through its admin socket
+	mountPath, err := filepath.Abs(options.MountPath)
+	if err != nil {
+		log.Warn("Mount::runPipeline : admin socket not available [%s]", err.Error())
+	}
+
+	// Automation reaches the same verbs over HTTP if asked for, with secrets redacted from the config it reports
+	admin.RegisterConfig(viper.AllSettings)
+	if libfuse.TakingOver() {
+		// Socket and admin API stay with the mount handing itself over till this process serves the mount
+		go func() {
+			if libfuse.WaitTakeover() == nil {
+				startAdmin(mountPath)
+			}
+		}()
+	} else {
+		startAdmin(mountPath)
+	}
+
+	// Settings which can change while mounted are read again from the config file on SIGHUP or a reload request
+	admin.RegisterHandler(admin.VerbReload, "mount", reloadConfig)
+	admin.RegisterHandler(admin.VerbUpgrade, "mount", upgradeMount)
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			log.Crit("Mount::runPipeline : SIGHUP received")
+			if _, err := reloadConfig(admin.Request{}); err != nil {
+				log.Err("Mount::runPipeline : failed to reload config [%s]", err.Error())
+			}
+		}
+	}()
+	defer signal.Stop(sighup)
+
+	// Commands like 'mount list --json' learn about the process through the status of the mount
+	startTime := time.Now()
+	configFile := options.ConfigFile
+	if configFile != "" {
+		configFile, _ = filepath.Abs(configFile)
+	}
+	// Kubernetes and sidecars learn from the health probe whether the mount is alive and ready, through the health
+	// routes of the admin API or the probe file
+	var probe *healthProbe
+	if options.AdminAPI.Address != "" || options.HealthProbe.File != "" {
+		probePath := mountPath
+		if probePath == "" {
+			probePath = options.MountPath
+		}
+		probe = startHealthProbe(options.HealthProbe, probePath, pipelineStorageCheck(pipeline))
+	}
+
+	admin.RegisterStatus("mount", func() map[string]string {
+		status := map[string]string{
+			admin.StatusPID:        pid,
+			admin.StatusVersion:    common.Blobfuse2Version,
+			admin.StatusConfigFile: configFile,
+			admin.StatusStartTime:  startTime.Format(time.RFC3339),
+		}
+		if restarts, supervised := supervisedRestarts(); supervised {
+			status[admin.StatusRestarts] = fmt.Sprintf("%d", restarts)
+		}
+		return status
+	})
 
-	err := pipeline.Start(ctx)
+	// Spans of the operations go to the OTLP collector if asked for, the pipeline links its components up for them
+	err = tracing.Start(options.Tracing)
 	if err != nil {
+		log.Err("Mount::runPipeline : tracing not available [%s]", err.Error())
+	}
+
+	err = pipeline.Start(ctx)
+	if err != nil {
+		probe.Stop()
+		admin.StopHTTP()
+		admin.Stop()
+		tracing.Stop()
 		log.Err("mount: error unable to start pipeline [%s]", err.Error())
-		return Destroy(fmt.Sprintf("unable to start pipeline [%s]", err.Error()))
+		return keepExitCode(err, Destroy(fmt.Sprintf("unable to start pipeline [%s]", err.Error())))
 	}
 
+	probe.Stop()
+	admin.StopHTTP()
+	admin.Stop()
+	tracing.Stop()
 	err = pipeline.Stop()
 	if err != nil {
 		log.Err("mount: error unable to stop pipeline [%s]", err.Error())
@@ -558,6 +727,23 @@ func runPipeline(pipeline *internal.Pipeline, ctx context.Context) error {
 	return nil
 }
 
+// startAdmin : Serve requests on the admin socket of the mount, and on the admin API if configured
+func startAdmin(mountPath string) {
+	if mountPath != "" {
+		err := admin.Start(mountPath)
+		if err != nil {
+			log.Warn("Mount::startAdmin : admin socket not available [%s]", err.Error())
+		}
+	}
+
+	if options.AdminAPI.Address != "" {
+		err := admin.StartHTTP(options.AdminAPI.Address, options.AdminAPI.Token)
+		if err != nil {
+			log.Err("Mount::startAdmin : admin API not available [%s]", err.Error())
+		}
+	}
+}
+
 func startMonitor(pid int) {
 	if common.EnableMonitoring {
 		log.Debug("Mount::startMonitor : pid = %v, config-file = %v", pid, options.ConfigFile)
@@ -573,20 +759,6 @@ func startMonitor(pid int) {
 	}
 }
 
-func sigusrHandler(pipeline *internal.Pipeline, ctx context.Context) daemon.SignalHandlerFunc {
-	return func(sig os.Signal) error {
-		log.Crit("Mount::sigusrHandler : Signal %d received", sig)
-
-		var err error
-		if sig == syscall.SIGUSR1 {
-			log.Crit("Mount::sigusrHandler : SIGUSR1 received")
-			config.OnConfigChange()
-		}
-
-		return err
-	}
-}
-
 func setGOConfig() {
 	// Ensure we always have more than 1 OS thread running goroutines, since there are issues with having just 1.
 	isOnlyOne := runtime.GOMAXPROCS(0) == 1
@@ -637,6 +809,9 @@ func init() {
 
 	mountCmd.AddCommand(mountListCmd)
 	mountCmd.AddCommand(mountAllCmd)
+	mountCmd.AddCommand(mountAddCmd)
+	mountCmd.AddCommand(mountRemoveCmd)
+	mountCmd.AddCommand(mountSnapshotCmd)
 
 	mountCmd.PersistentFlags().StringVar(&options.ConfigFile, "config-file", "",
 		"Configures the path for the file where the account credentials are provided. Default is config.yaml in current directory.")
@@ -648,6 +823,13 @@ func init() {
 	mountCmd.PersistentFlags().StringVar(&options.PassPhrase, "passphrase", "",
 		"Key to decrypt config file. Can also be specified by env-variable BLOBFUSE2_SECURE_CONFIG_PASSPHRASE.\nKey length shall be 16 (AES-128), 24 (AES-192), or 32 (AES-256) bytes in length.")
 
+	mountCmd.PersistentFlags().StringVar(&options.PassphraseSource, "passphrase-source", "",
+		"Fetch the key to decrypt config file from Azure Key Vault, keyvault://<vault>/<secret>, or the keyring of the OS, keyring://<key name>.")
+
+	mountCmd.PersistentFlags().StringVar(&options.EnvFile, "env-file", "",
+		"File of KEY=VALUE lines, e.g. AZURE_STORAGE_ACCESS_KEY or BLOBFUSE2_SECURE_CONFIG_PASSPHRASE, exported before the config is read.")
+	_ = mountCmd.MarkPersistentFlagFilename("env-file")
+
 	mountCmd.PersistentFlags().String("log-type", "syslog", "Type of logger to be used by the system. Set to syslog by default. Allowed values are silent|syslog|base.")
 	config.BindPFlag("logging.type", mountCmd.PersistentFlags().Lookup("log-type"))
 	_ = mountCmd.RegisterFlagCompletionFunc("log-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -661,6 +843,13 @@ func init() {
 		return []string{"LOG_OFF", "LOG_CRIT", "LOG_ERR", "LOG_WARNING", "LOG_INFO", "LOG_TRACE", "LOG_DEBUG"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
+	mountCmd.PersistentFlags().String("log-format", "text",
+		"Format of the lines of the base logger, text or json. In foreground json is logged to stdout unless log-type or log-file-path say otherwise.")
+	config.BindPFlag("logging.format", mountCmd.PersistentFlags().Lookup("log-format"))
+	_ = mountCmd.RegisterFlagCompletionFunc("log-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
 	mountCmd.PersistentFlags().String("log-file-path",
 		common.DefaultLogFilePath, "Configures the path for log files. Default is "+common.DefaultLogFilePath)
 	config.BindPFlag("logging.file-path", mountCmd.PersistentFlags().Lookup("log-file-path"))
@@ -703,8 +892,70 @@ func init() {
 
 	mountCmd.PersistentFlags().DurationVar(&options.WaitForMount, "wait-for-mount", 5*time.Second, "Let parent process wait for given timeout before exit")
 
+	mountCmd.PersistentFlags().String("admin-api", "",
+		"Serve health, metrics, config, cache invalidation and log level over HTTP, on unix:<socket path> or 127.0.0.1:<port>. Token is taken from BLOBFUSE2_ADMIN_API_TOKEN.")
+	config.BindPFlag("admin-api.address", mountCmd.PersistentFlags().Lookup("admin-api"))
+	config.BindEnv("admin-api.token", "BLOBFUSE2_ADMIN_API_TOKEN")
+
+	mountCmd.PersistentFlags().String("health-probe-file", "",
+		"Write live=, ready= and reason= of the mount to this file after every check, for exec probes.")
+	config.BindPFlag("health-probe.file", mountCmd.PersistentFlags().Lookup("health-probe-file"))
+	_ = mountCmd.MarkPersistentFlagFilename("health-probe-file")
+
+	mountCmd.PersistentFlags().Bool("supervise", false,
+		"Run the mount in a child process and mount again, with backoff, when it crashes or hangs.")
+	config.BindPFlag("supervisor.enabled", mountCmd.PersistentFlags().Lookup("supervise"))
+
+	mountCmd.PersistentFlags().Bool("dry-run", false,
+		"Configure every component and check the storage endpoint can be reached without credentials, then print a report and exit without mounting.")
+	config.BindPFlag("dry-run", mountCmd.PersistentFlags().Lookup("dry-run"))
+
+	mountCmd.PersistentFlags().StringVar(&dryRunAzurite, "azurite", "",
+		"With --dry-run, list the container on Azurite, the storage emulator, at this endpoint in place of the configured account. Default endpoint is "+defaultAzuriteEndpoint+".")
+	mountCmd.PersistentFlags().Lookup("azurite").NoOptDefVal = defaultAzuriteEndpoint
+
+	mountCmd.PersistentFlags().String("preset", "",
+		"Defaults tuned for a workload, one of general|hpc|ml-training|backup. Values set in config, environment or flags take precedence.")
+	config.BindPFlag("preset", mountCmd.PersistentFlags().Lookup("preset"))
+	_ = mountCmd.RegisterFlagCompletionFunc("preset", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return presetNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	mountCmd.PersistentFlags().Uint32("shutdown-timeout-sec", defaultShutdownTimeout,
+		"Seconds modified files get to upload on SIGTERM or SIGINT before the mount is unmounted.")
+	config.BindPFlag("shutdown-timeout-sec", mountCmd.PersistentFlags().Lookup("shutdown-timeout-sec"))
+
+	mountCmd.PersistentFlags().String("shutdown-cache", "",
+		"What becomes of the file cache on SIGTERM or SIGINT, preserve or cleanup. Default is what the file cache is configured to do on unmount.")
+	config.BindPFlag("shutdown-cache", mountCmd.PersistentFlags().Lookup("shutdown-cache"))
+
+	mountCmd.PersistentFlags().Uint64("memory-limit-mb", 0,
+		"Memory the mount may use in MB, held by its cgroup and by buffers of the components giving way. No limit by default.")
+	config.BindPFlag("resource-limits.memory-mb", mountCmd.PersistentFlags().Lookup("memory-limit-mb"))
+
+	mountCmd.PersistentFlags().Uint32("cpu-limit-percent", 0,
+		"CPU the mount may use in percent of one core, e.g. 200 for two cores. No limit by default.")
+	config.BindPFlag("resource-limits.cpu-percent", mountCmd.PersistentFlags().Lookup("cpu-limit-percent"))
+
+	mountCmd.PersistentFlags().StringSlice("components", []string{},
+		"Components of the pipeline in order, e.g. libfuse,stream,azstorage. Default is libfuse, file_cache, attr_cache and azstorage.")
+	config.BindPFlag("components", mountCmd.PersistentFlags().Lookup("components"))
+
+	mountCmd.Flags().StringVar(&errorOutput, "output", outputText,
+		"Format errors are printed in, text or json. With json a line like {\"exitCode\":4,\"cause\":\"auth\",\"error\":\"...\"} is printed to stdout.")
+	_ = mountCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputText, outputJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	// Every other key of the components can be given as --<component>.<key> or BLOBFUSE2_<COMPONENT>_<KEY>
+	config.AddSchemaFlags()
+
 	config.AttachToFlagSet(mountCmd.PersistentFlags())
+
+	// Containers are listed with the credentials of the config given on the command line
+	config.RegisterFlagCompletionFunc("container-name", completeContainers)
 	config.AttachFlagCompletions(mountCmd)
+	_ = mountCmd.RegisterFlagCompletionFunc("azstorage.container", completeContainers)
 	config.AddConfigChangeEventListener(config.ConfigChangeEventHandlerFunc(OnConfigChange))
 }
 
@@ -0,0 +1,158 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+type mountServiceOptions struct {
+	Service string
+	Options []string // key=value
+}
+
+var mountServiceOpts mountServiceOptions
+
+var mountAddCmd = &cobra.Command{
+	Use:               "add <name> <mount path>",
+	Short:             "Start a named mount in the running blobfuse2 service",
+	Long:              "Start a named mount in the running blobfuse2 service. The mount uses the given config file, or the one of the service, and each --option key=value is passed on as --key=value. Mounts added this way are not written to the config file of the service.",
+	SuggestFor:        []string{"ad", "addd"},
+	Example:           "blobfuse2 mount add logs ~/logs --config-file=logs.yaml\nblobfuse2 mount add logs ~/logs --option azstorage.container=logs --option read-only=true",
+	Args:              cobra.ExactArgs(2),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := buildMountSpec(args[0], args[1], options.ConfigFile, mountServiceOpts.Options)
+		if err != nil {
+			return withExitCode(exitUsage, err)
+		}
+
+		resp, err := sendService(mountServiceOpts.Service, admin.Request{Verb: admin.VerbMountAdd, Mount: &spec})
+		if err != nil {
+			return fmt.Errorf("failed to add mount %s [%s]", spec.Name, err.Error())
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), resp.Message)
+		return nil
+	},
+}
+
+var mountRemoveCmd = &cobra.Command{
+	Use:               "remove <name>",
+	Short:             "Unmount a named mount of the running blobfuse2 service",
+	Long:              "Unmount a named mount of the running blobfuse2 service and forget it. The command waits for the mount to go away, a mount which does not stop within service.stop-timeout-sec is killed and detached.",
+	SuggestFor:        []string{"rm", "remov"},
+	Example:           "blobfuse2 mount remove logs",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServiceMounts,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := sendService(mountServiceOpts.Service, admin.Request{Verb: admin.VerbMountRemove, Mount: &admin.MountSpec{Name: args[0]}})
+		if err != nil {
+			return fmt.Errorf("failed to remove mount %s [%s]", args[0], err.Error())
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), resp.Message)
+		return nil
+	},
+}
+
+// buildMountSpec : Spec of a named mount with paths made absolute, as the service process runs in another directory
+func buildMountSpec(name string, mountPath string, configFile string, opts []string) (admin.MountSpec, error) {
+	spec := admin.MountSpec{Name: name}
+
+	var err error
+	spec.MountPath, err = filepath.Abs(common.ExpandPath(mountPath))
+	if err != nil {
+		return spec, err
+	}
+
+	if configFile != "" {
+		spec.ConfigFile, err = filepath.Abs(common.ExpandPath(configFile))
+		if err != nil {
+			return spec, err
+		}
+	}
+
+	for _, opt := range opts {
+		key, value, found := strings.Cut(opt, "=")
+		key = strings.TrimPrefix(strings.TrimSpace(key), "--")
+		if !found || key == "" {
+			return spec, fmt.Errorf("invalid option %q, shall be key=value", opt)
+		}
+		if spec.Options == nil {
+			spec.Options = make(map[string]string)
+		}
+		spec.Options[key] = value
+	}
+
+	return spec, nil
+}
+
+// completeServiceMounts : Names of the mounts of the service, for shell completion
+func completeServiceMounts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	resp, err := admin.SendTimeout(admin.ServiceAddress(mountServiceOpts.Service), admin.Request{Verb: admin.VerbMountList}, mountStatusTimeout)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(resp.Mounts))
+	for _, m := range resp.Mounts {
+		if strings.HasPrefix(m.Name, toComplete) {
+			names = append(names, m.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	mountAddCmd.Flags().StringArrayVar(&mountServiceOpts.Options, "option", []string{},
+		"Setting of the mount as key=value, passed on to it as --key=value. Can be given more than once.")
+
+	for _, cmd := range []*cobra.Command{mountAddCmd, mountRemoveCmd, mountListCmd} {
+		cmd.Flags().StringVar(&mountServiceOpts.Service, "service", defaultServiceName,
+			"Name of the blobfuse2 service owning the named mounts")
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -53,10 +54,21 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// containerOverride : Config applied on top of the common one for containers matching any of the patterns
+type containerOverride struct {
+	Containers []string               `config:"containers"`
+	Config     map[string]interface{} `config:"config"`
+}
+
 type containerListingOptions struct {
-	AllowList        []string `config:"container-allowlist"`
-	DenyList         []string `config:"container-denylist"`
+	AllowList        []string            `config:"container-allowlist"`
+	DenyList         []string            `config:"container-denylist"`
+	Overrides        []containerOverride `config:"overrides"`
 	blobfuse2BinPath string
+
+	// Patterns given on the command line, added to the lists from config
+	include []string
+	exclude []string
 }
 
 var mountAllOpts containerListingOptions
@@ -132,6 +144,7 @@ func processCommand() error {
 		FileCount:   options.Logging.LogFileCount,
 		Level:       logLevel,
 		TimeTracker: options.Logging.TimeTracker,
+		Format:      options.Logging.Format,
 	})
 
 	if err != nil {
@@ -159,10 +172,17 @@ func processCommand() error {
 	if err != nil {
 		log.Warn("mount all: mountall config error (invalid config attributes) [%s]\n", err.Error())
 	}
+	mountAllOpts.AllowList = append(mountAllOpts.AllowList, mountAllOpts.include...)
+	mountAllOpts.DenyList = append(mountAllOpts.DenyList, mountAllOpts.exclude...)
+
+	if len(mountAllOpts.Overrides) > 0 && !configFileExists {
+		return fmt.Errorf("per container overrides need a config file")
+	}
 
 	// Validate config is to be secured on write or not
-	if options.PassPhrase == "" {
-		options.PassPhrase = os.Getenv(SecureConfigEnvName)
+	options.PassPhrase, err = lookupPassphrase(options.PassPhrase, options.PassphraseSource)
+	if err != nil {
+		return err
 	}
 
 	if options.SecureConfig && options.PassPhrase == "" {
@@ -218,47 +238,84 @@ func getContainerList() ([]string, error) {
 	return containerList, nil
 }
 
-// FiterAllowedContainer : Filter which containers are allowed to be mounted
+// filterAllowedContainerList : Filter which containers are allowed to be mounted, lists hold names or glob patterns
 func filterAllowedContainerList(containers []string) []string {
-	allowListing := false
-	if len(mountAllOpts.AllowList) > 0 {
-		allowListing = true
+	var filterList []string
+	for _, container := range containers {
+		// Only containers in the allowlist shall be allowed, if there is one, else all but those in the denylist
+		allowed := !matchContainer(mountAllOpts.DenyList, container)
+		if len(mountAllOpts.AllowList) > 0 {
+			allowed = matchContainer(mountAllOpts.AllowList, container)
+		}
+
+		if allowed {
+			filterList = append(filterList, container)
+		}
 	}
 
-	// Convert the entire container list into a map
-	var filterContainer = make(map[string]bool)
-	for _, container := range containers {
-		filterContainer[container] = !allowListing
+	return filterList
+}
+
+// matchContainer : Whether the container is named by any of the patterns
+func matchContainer(patterns []string, container string) bool {
+	for _, pattern := range patterns {
+		match, err := path.Match(pattern, container)
+		if match || (err != nil && pattern == container) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Now based on allow or deny list mark the containers
-	if allowListing {
-		// Only containers in this list shall be allowed
-		for _, container := range mountAllOpts.AllowList {
-			_, found := filterContainer[container]
-			if found {
-				filterContainer[container] = true
-			}
+// applyOverrides : Set the config of the matching overrides, later ones win, and return a function restoring the config
+func applyOverrides(container string) func() {
+	previous := make(map[string]interface{})
+	for _, override := range mountAllOpts.Overrides {
+		if !matchContainer(override.Containers, container) {
+			continue
 		}
-	} else {
-		// Containers in this list shall not be allowed
-		for _, container := range mountAllOpts.DenyList {
-			_, found := filterContainer[container]
-			if found {
-				filterContainer[container] = false
+
+		for key, val := range flattenConfig("", override.Config) {
+			if _, saved := previous[key]; !saved {
+				previous[key] = viper.Get(key)
 			}
+			viper.Set(key, val)
 		}
 	}
 
-	// Consolidate only containers that are allowed now
-	var filterList []string
-	for container, allowed := range filterContainer {
-		if allowed {
-			filterList = append(filterList, container)
+	return func() {
+		for key, val := range previous {
+			viper.Set(key, val)
 		}
 	}
+}
 
-	return filterList
+// flattenConfig : Turn nested config sections into dotted keys, e.g. file_cache.max-size-mb
+func flattenConfig(prefix string, conf map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	for key, val := range conf {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch section := val.(type) {
+		case map[string]interface{}:
+			for k, v := range flattenConfig(key, section) {
+				flat[k] = v
+			}
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(section))
+			for k, v := range section {
+				converted[fmt.Sprint(k)] = v
+			}
+			for k, v := range flattenConfig(key, converted) {
+				flat[k] = v
+			}
+		default:
+			flat[key] = val
+		}
+	}
+	return flat
 }
 
 // mountAllContainers : Iterate allowed container list and create config file and mount path for them
@@ -309,7 +366,9 @@ func mountAllContainers(containerList []string, configFile string, mountPath str
 			viper.Set("file_cache.path", filepath.Join(fileCachePath, container))
 
 			// Create config file with container specific configs
+			restore := applyOverrides(container)
 			err := writeConfigFile(contConfigFile)
+			restore()
 			if err != nil {
 				return err
 			}
@@ -350,13 +409,17 @@ func updateCliParams(cliParams *[]string, key string, val string) {
 }
 
 func writeConfigFile(contConfigFile string) error {
-	if options.SecureConfig {
-		allConf := viper.AllSettings()
-		confStream, err := yaml.Marshal(allConf)
-		if err != nil {
-			return fmt.Errorf("failed to marshall yaml content")
-		}
+	allConf := viper.AllSettings()
 
+	// Values taken from environment variables or files are written as references, not in plain text
+	config.RestoreReferences(allConf)
+
+	confStream, err := yaml.Marshal(allConf)
+	if err != nil {
+		return fmt.Errorf("failed to marshall yaml content")
+	}
+
+	if options.SecureConfig {
 		cipherText, err := common.EncryptData(confStream, []byte(options.PassPhrase))
 		if err != nil {
 			return fmt.Errorf("failed to encrypt yaml content [%s]", err.Error())
@@ -368,7 +431,7 @@ func writeConfigFile(contConfigFile string) error {
 		}
 	} else {
 		// Write modified config as per container to a new config file
-		err := viper.WriteConfigAs(contConfigFile)
+		err = os.WriteFile(contConfigFile, confStream, 0644)
 		if err != nil {
 			return fmt.Errorf("failed to write config file [%s]", err.Error())
 		}
@@ -382,9 +445,15 @@ func buildCliParamForMount() []string {
 	cliParam = append(cliParam, "mount")
 	cliParam = append(cliParam, "<mount-path>")
 	cliParam = append(cliParam, "--config-file=<conf_file>")
-	for _, opt := range os.Args[4:] {
-		if !ignoreCliParam(opt) {
-			cliParam = append(cliParam, opt)
+	args := os.Args[4:]
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--include" || args[i] == "--exclude" {
+			// Pattern given as the next argument
+			i++
+			continue
+		}
+		if !ignoreCliParam(args[i]) {
+			cliParam = append(cliParam, args[i])
 		}
 	}
 	cliParam = append(cliParam, "--disable-version-check=true")
@@ -393,5 +462,14 @@ func buildCliParamForMount() []string {
 }
 
 func ignoreCliParam(opt string) bool {
-	return strings.HasPrefix(opt, "--config-file")
+	return strings.HasPrefix(opt, "--config-file") || strings.HasPrefix(opt, "--include=") || strings.HasPrefix(opt, "--exclude=")
+}
+
+func init() {
+	mountAllCmd.Flags().StringSliceVar(&mountAllOpts.include, "include", []string{},
+		"Mount only containers matching these names or glob patterns, added to container-allowlist of mountall config.")
+	mountAllCmd.Flags().StringSliceVar(&mountAllOpts.exclude, "exclude", []string{},
+		"Do not mount containers matching these names or glob patterns, added to container-denylist of mountall config.")
+	_ = mountAllCmd.RegisterFlagCompletionFunc("include", completeContainers)
+	_ = mountAllCmd.RegisterFlagCompletionFunc("exclude", completeContainers)
 }
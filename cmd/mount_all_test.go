@@ -0,0 +1,118 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type mountAllTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *mountAllTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+}
+
+func (suite *mountAllTestSuite) cleanupTest() {
+	mountAllOpts = containerListingOptions{}
+	viper.Reset()
+}
+
+func TestMountAllCommand(t *testing.T) {
+	suite.Run(t, new(mountAllTestSuite))
+}
+
+func (suite *mountAllTestSuite) TestFilterContainers() {
+	defer suite.cleanupTest()
+	containers := []string{"logs-2023", "logs-2024", "data", "backup"}
+
+	suite.assert.Equal(containers, filterAllowedContainerList(containers))
+
+	mountAllOpts.DenyList = []string{"logs-*", "backup"}
+	suite.assert.Equal([]string{"data"}, filterAllowedContainerList(containers))
+
+	// Allowlist takes precedence over denylist
+	mountAllOpts.AllowList = []string{"logs-202[4-9]", "backup", "[invalid"}
+	suite.assert.Equal([]string{"logs-2024", "backup"}, filterAllowedContainerList(containers))
+
+	mountAllOpts.AllowList = []string{"nothing"}
+	suite.assert.Empty(filterAllowedContainerList(containers))
+}
+
+func (suite *mountAllTestSuite) TestApplyOverrides() {
+	defer suite.cleanupTest()
+	conf := `file_cache:
+  max-size-mb: 100
+mountall:
+  overrides:
+    - containers: ["logs-*"]
+      config:
+        read-only: true
+        file_cache:
+          max-size-mb: 2048
+    - containers: ["logs-archive"]
+      config:
+        file_cache:
+          max-size-mb: 10
+`
+	suite.assert.Nil(config.ReadConfigFromReader(strings.NewReader(conf)))
+	suite.assert.Nil(config.UnmarshalKey("mountall", &mountAllOpts))
+	suite.assert.Len(mountAllOpts.Overrides, 2)
+
+	restore := applyOverrides("logs-2024")
+	suite.assert.Equal(2048, viper.GetInt("file_cache.max-size-mb"))
+	suite.assert.True(viper.GetBool("read-only"))
+	restore()
+	suite.assert.Equal(100, viper.GetInt("file_cache.max-size-mb"))
+	suite.assert.Nil(viper.Get("read-only"))
+
+	// Later overrides win
+	restore = applyOverrides("logs-archive")
+	suite.assert.Equal(10, viper.GetInt("file_cache.max-size-mb"))
+	restore()
+
+	restore = applyOverrides("data")
+	suite.assert.Equal(100, viper.GetInt("file_cache.max-size-mb"))
+	suite.assert.NotContains(viper.AllSettings(), "read-only")
+	restore()
+}
@@ -0,0 +1,147 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/component/azstorage"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+)
+
+// Account of Azurite, the storage emulator, and its key. Both are publicly documented and the same for every Azurite.
+const (
+	azuriteAccount         = "devstoreaccount1"
+	azuriteKey             = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	defaultAzuriteEndpoint = "http://127.0.0.1:10000/" + azuriteAccount
+)
+
+// Endpoint of Azurite a dry run lists the container on, empty to only check the configured endpoint can be reached
+var dryRunAzurite string
+
+// storageEndpoint : Component which tells the storage endpoint it talks to, see azstorage.Endpoint
+type storageEndpoint interface {
+	Endpoint() string
+}
+
+// applyAzurite : Point azstorage at the account of Azurite in place of the configured account and credentials
+func applyAzurite(endpoint string) {
+	config.Set("azstorage.type", "block")
+	config.Set("azstorage.use-adls", "false")
+	config.Set("azstorage.mode", "key")
+	config.Set("azstorage.account-name", azuriteAccount)
+	config.Set("azstorage.account-key", azuriteKey)
+	config.Set("azstorage.endpoint", endpoint)
+	config.Set("azstorage.use-http", strconv.FormatBool(strings.HasPrefix(endpoint, "http://")))
+}
+
+// dryRunMount : Configure every component of the pipeline the mount would run and check storage can be reached,
+// printing what was found instead of mounting
+func dryRunMount(w io.Writer) error {
+	if dryRunAzurite != "" {
+		applyAzurite(dryRunAzurite)
+	}
+
+	report := &doctorReport{}
+	report.add("config", checkOK, fmt.Sprintf("pipeline %s", strings.Join(options.Components, " -> ")), "")
+	report.add("mount", checkOK, fmt.Sprintf("%s can be mounted on", options.MountPath), "")
+
+	code := exitSuccess
+	pipeline, errs := internal.CheckPipeline(options.Components, true)
+	for i, name := range options.Components {
+		if errs[i] != nil {
+			report.add(name, checkFail, errs[i].Error(), fmt.Sprintf("fix the %s section of the config", name))
+			code = exitConfig
+		} else {
+			report.add(name, checkOK, "configured", "")
+		}
+	}
+
+	if storageCode := checkDryRunStorage(report, pipeline); code == exitSuccess {
+		code = storageCode
+	}
+
+	report.print(w)
+	if code != exitSuccess {
+		return withExitCode(code, fmt.Errorf("dry run found %d problem(s), %s was not mounted", report.count(checkFail), options.MountPath))
+	}
+
+	fmt.Fprintf(w, "\nDry run passed, %s was not mounted\n", options.MountPath)
+	return nil
+}
+
+// checkDryRunStorage : Connect to the storage endpoint without credentials, or list the container on Azurite. Returns
+// the code the mount would exit with on the problem found.
+func checkDryRunStorage(report *doctorReport, pipeline *internal.Pipeline) int {
+	comp := pipeline.Component("azstorage")
+	if comp == nil {
+		report.add("storage", checkSkip, "azstorage is not configured", "")
+		return exitSuccess
+	}
+
+	conf := azstorage.AzStorageOptions{}
+	_ = config.UnmarshalKey("azstorage", &conf)
+
+	if dryRunAzurite == "" {
+		failed := report.count(checkFail)
+		if endpoint, ok := comp.(storageEndpoint); ok {
+			checkEndpoint(report, endpoint.Endpoint(), conf)
+		}
+		report.add("container", checkSkip, "not listed on a dry run, pass --azurite to list it on Azurite", "")
+
+		if report.count(checkFail) > failed {
+			return exitStorageUnreachable
+		}
+		return exitSuccess
+	}
+
+	err := pipelineStorageCheck(pipeline)()
+	if err != nil {
+		detail, fix := classifyStorageError(err, conf)
+		code := exitContainerMissing
+		if !strings.Contains(err.Error(), "ContainerNotFound") {
+			fix = "start Azurite, e.g. docker run -p 10000:10000 mcr.microsoft.com/azure-storage/azurite azurite-blob --blobHost 0.0.0.0"
+			code = exitStorageUnreachable
+		}
+		report.add("container", checkFail, fmt.Sprintf("%s on Azurite at %s", detail, dryRunAzurite), fix)
+		return code
+	}
+
+	report.add("container", checkOK, fmt.Sprintf("listed container %s on Azurite at %s", conf.Container, dryRunAzurite), "")
+	return exitSuccess
+}
@@ -0,0 +1,186 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type mountDryRunTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *mountDryRunTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	options = mountOptions{}
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *mountDryRunTestSuite) cleanupTest() {
+	resetCLIFlags(*mountCmd)
+	dryRunAzurite = ""
+	viper.Reset()
+}
+
+func TestMountDryRun(t *testing.T) {
+	suite.Run(t, new(mountDryRunTestSuite))
+}
+
+// dryRunConfig : Config of a mount with the account at the endpoint, which a dry run reports on
+func (suite *mountDryRunTestSuite) dryRunConfig(endpoint string) string {
+	confFile := filepath.Join(suite.T().TempDir(), "config.yaml")
+	conf := fmt.Sprintf("logging:\n  type: silent\ncomponents:\n  - attr_cache\n  - azstorage\nazstorage:\n  type: block\n  account-name: myaccount\n  account-key: %s\n  mode: key\n  container: mycontainer\n  endpoint: %s\n", azuriteKey, endpoint)
+	suite.assert.Nil(os.WriteFile(confFile, []byte(conf), 0644))
+	return confFile
+}
+
+func (suite *mountDryRunTestSuite) TestApplyAzurite() {
+	defer suite.cleanupTest()
+
+	applyAzurite(defaultAzuriteEndpoint)
+
+	storage := struct {
+		AccountName string `config:"account-name"`
+		AccountKey  string `config:"account-key"`
+		Mode        string `config:"mode"`
+		Endpoint    string `config:"endpoint"`
+		UseHTTP     bool   `config:"use-http"`
+	}{}
+	suite.assert.Nil(config.UnmarshalKey("azstorage", &storage))
+	suite.assert.Equal(azuriteAccount, storage.AccountName)
+	suite.assert.Equal(azuriteKey, storage.AccountKey)
+	suite.assert.Equal("key", storage.Mode)
+	suite.assert.Equal("http://127.0.0.1:10000/devstoreaccount1", storage.Endpoint)
+	suite.assert.True(storage.UseHTTP)
+}
+
+func (suite *mountDryRunTestSuite) TestAzuriteWithoutDryRun() {
+	defer suite.cleanupTest()
+
+	mntDir := suite.T().TempDir()
+	op, err := executeCommandC(rootCmd, "mount", mntDir, "--config-file="+suite.dryRunConfig("http://127.0.0.1:1"), "--azurite")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitUsage, exitCode(err))
+	suite.assert.Contains(op, "--azurite is only used with --dry-run")
+}
+
+func (suite *mountDryRunTestSuite) TestDryRun() {
+	defer suite.cleanupTest()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.T().Errorf("dry run sent a request to storage %s", r.URL.String())
+	}))
+	defer server.Close()
+
+	mntDir := suite.T().TempDir()
+	op, err := executeCommandC(rootCmd, "mount", mntDir, "--config-file="+suite.dryRunConfig(server.URL), "--dry-run")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "pipeline attr_cache -> azstorage")
+	suite.assert.Contains(op, "[OK  ] azstorage")
+	suite.assert.Contains(op, "[OK  ] endpoint")
+	suite.assert.Contains(op, "[SKIP] container")
+	suite.assert.Contains(op, "Dry run passed")
+	suite.assert.False(common.IsDirectoryMounted(mntDir))
+}
+
+func (suite *mountDryRunTestSuite) TestDryRunInvalidConfig() {
+	defer suite.cleanupTest()
+
+	mntDir := suite.T().TempDir()
+	op, err := executeCommandC(rootCmd, "mount", mntDir, "--config-file="+suite.dryRunConfig("http://127.0.0.1:1"),
+		"--dry-run", "--attr_cache.inventory-file=/nonexistent/inventory.csv")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitConfig, exitCode(err))
+	suite.assert.Contains(op, "[FAIL] attr_cache")
+	suite.assert.Contains(op, "[OK  ] azstorage")
+	suite.assert.Contains(op, "[FAIL] endpoint")
+	suite.assert.Contains(op, "was not mounted")
+}
+
+func (suite *mountDryRunTestSuite) TestDryRunUnreachable() {
+	defer suite.cleanupTest()
+
+	mntDir := suite.T().TempDir()
+	_, err := executeCommandC(rootCmd, "mount", mntDir, "--config-file="+suite.dryRunConfig("http://127.0.0.1:1"), "--dry-run")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitStorageUnreachable, exitCode(err))
+}
+
+func (suite *mountDryRunTestSuite) TestDryRunAzurite() {
+	defer suite.cleanupTest()
+
+	listed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("comp") == "list" && r.URL.Path == "/"+azuriteAccount+"/mycontainer" {
+			listed = true
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs></Blobs><NextMarker/></EnumerationResults>`)
+			return
+		}
+		w.Header().Set("x-ms-error-code", "ContainerNotFound")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><Error><Code>ContainerNotFound</Code><Message>The specified container does not exist.</Message></Error>`)
+	}))
+	defer server.Close()
+
+	mntDir := suite.T().TempDir()
+	op, err := executeCommandC(rootCmd, "mount", mntDir, "--config-file="+suite.dryRunConfig("http://127.0.0.1:1"),
+		"--dry-run", "--azurite="+server.URL+"/"+azuriteAccount)
+	suite.assert.Nil(err)
+	suite.assert.True(listed)
+	suite.assert.Contains(op, "[OK  ] container  listed container mycontainer on Azurite")
+
+	op, err = executeCommandC(rootCmd, "mount", mntDir, "--config-file="+suite.dryRunConfig("http://127.0.0.1:1"),
+		"--dry-run", "--azurite="+server.URL+"/"+azuriteAccount, "--azstorage.container=missing")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitContainerMissing, exitCode(err))
+	suite.assert.Contains(op, "container missing does not exist on Azurite")
+}
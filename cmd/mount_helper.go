@@ -0,0 +1,260 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sevlyar/go-daemon"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// For an fstab entry of type blobfuse2, mount(8) runs "mount.blobfuse2 <spec> <dir> [-sfnvrw] [-o options]"
+const mountHelperPrefix = "mount."
+
+// Exit codes mount(8) documents for its helpers
+const (
+	mountExitUsage   = 1
+	mountExitFailure = 32
+)
+
+// FUSE options which stay in the -o list, everything else shall name a flag of the mount command
+var helperFuseOptions = []string{
+	"allow_other", "allow_root", "attr_timeout", "entry_timeout", "negative_timeout",
+	"ro", "nonempty", "umask", "uid", "gid", "direct_io",
+}
+
+// helperUsageError : Invalid options, reported to mount(8) as incorrect invocation
+type helperUsageError struct {
+	err error
+}
+
+func (e helperUsageError) Error() string {
+	return e.err.Error()
+}
+
+// isMountHelper : Whether blobfuse2 was invoked through its mount.blobfuse2 link
+func isMountHelper(binary string) bool {
+	return strings.HasPrefix(filepath.Base(binary), mountHelperPrefix)
+}
+
+// lookupMountFlag : Find a flag the mount command accepts, including the ones inherited from root
+func lookupMountFlag(name string) *pflag.Flag {
+	if name == "o" {
+		return nil
+	}
+	if f := mountCmd.Flags().Lookup(name); f != nil {
+		return f
+	}
+	if f := mountCmd.PersistentFlags().Lookup(name); f != nil {
+		return f
+	}
+	return rootCmd.PersistentFlags().Lookup(name)
+}
+
+// helperArgs : Convert the arguments mount(8) gives to its helper into arguments of the mount command.
+// Each -o option is either a FUSE option or key[=value] naming a mount flag, e.g.
+// -o allow_other,config-file=/etc/blobfuse2.yaml,secure-config,env-file=/etc/blobfuse2.env
+// The spec, unless none, is the container to mount. With -f nothing is mounted once the options are valid.
+func helperArgs(cmdArgs []string) (args []string, fake bool, err error) {
+	positional := make([]string, 0, 2)
+	opts := make([]string, 0)
+	sloppy := false
+
+	for i := 1; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		switch {
+		case arg == "-o" || arg == "-t" || arg == "-N":
+			i++
+			if i >= len(cmdArgs) {
+				return nil, false, fmt.Errorf("option %s requires a value", arg)
+			}
+			if arg == "-o" {
+				opts = append(opts, strings.Split(cmdArgs[i], ",")...)
+			}
+
+		case strings.HasPrefix(arg, "-o"):
+			opts = append(opts, strings.Split(arg[2:], ",")...)
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			for _, c := range arg[1:] {
+				switch c {
+				case 's':
+					sloppy = true
+				case 'f':
+					fake = true
+				case 'r':
+					opts = append(opts, "ro")
+				case 'n', 'v', 'w':
+				default:
+					return nil, false, fmt.Errorf("unknown option -%c", c)
+				}
+			}
+
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) != 2 {
+		return nil, false, errors.New("usage: mount.blobfuse2 <container|none> <mount path> [-sfnvrw] [-o options]")
+	}
+	spec, mountPath := positional[0], positional[1]
+
+	fuseOpts := make([]string, 0)
+	flags := make([]string, 0)
+	containerSet := false
+
+	for _, o := range opts {
+		if o == "" {
+			continue
+		}
+
+		// Options given as --key=value before this helper existed are passed on as they are
+		if strings.HasPrefix(o, "--") {
+			flags = append(flags, o)
+			containerSet = containerSet || strings.HasPrefix(o, "--container-name=")
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(o, "=")
+		if contains(helperFuseOptions, key) || ignoreFuseOptions(o) {
+			fuseOpts = append(fuseOpts, o)
+			continue
+		}
+
+		flag := lookupMountFlag(key)
+		if flag == nil {
+			if sloppy {
+				continue
+			}
+			return nil, false, fmt.Errorf("unknown option %s", key)
+		}
+
+		if !hasValue {
+			if flag.Value.Type() != "bool" {
+				return nil, false, fmt.Errorf("option %s requires a value", key)
+			}
+			value = "true"
+		}
+		flags = append(flags, fmt.Sprintf("--%s=%s", key, value))
+		containerSet = containerSet || key == "container-name"
+	}
+
+	args = []string{"mount", mountPath}
+	if len(fuseOpts) > 0 {
+		args = append(args, "-o", strings.Join(fuseOpts, ","))
+	}
+	if spec != "none" && spec != "blobfuse2" && !containerSet {
+		args = append(args, "--container-name="+spec)
+	}
+	args = append(args, flags...)
+
+	return args, fake, nil
+}
+
+// executeMountHelper : Run the mount and return the exit code mount(8) expects, errors go to stderr
+func executeMountHelper(cmdArgs []string) int {
+	name := filepath.Base(cmdArgs[0])
+
+	args, fake, err := helperArgs(cmdArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, err.Error())
+		return mountExitUsage
+	}
+	if fake {
+		return 0
+	}
+
+	rootCmd.SilenceErrors = true
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return helperUsageError{err}
+	})
+	rootCmd.SetArgs(args)
+
+	_, err = rootCmd.ExecuteC()
+	if err != nil {
+		if daemon.WasReborn() {
+			// The parent prefixes what the daemonized child wrote to stderr
+			fmt.Fprintln(os.Stderr, strings.TrimSpace(err.Error()))
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, strings.TrimSpace(err.Error()))
+		}
+		if errors.As(err, &helperUsageError{}) {
+			return mountExitUsage
+		}
+		return mountExitFailure
+	}
+	return 0
+}
+
+// loadEnvFile : Export the KEY=VALUE lines of the file, so credentials need not be written to fstab
+func loadEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("line %d is not KEY=VALUE", lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if err = os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
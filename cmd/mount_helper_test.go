@@ -0,0 +1,135 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type mountHelperTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *mountHelperTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+}
+
+func TestMountHelper(t *testing.T) {
+	suite.Run(t, new(mountHelperTestSuite))
+}
+
+func (suite *mountHelperTestSuite) TestIsMountHelper() {
+	suite.assert.True(isMountHelper("/sbin/mount.blobfuse2"))
+	suite.assert.True(isMountHelper("mount.blobfuse2"))
+	suite.assert.False(isMountHelper("/usr/bin/blobfuse2"))
+}
+
+func (suite *mountHelperTestSuite) TestHelperArgs() {
+	var inputs = []struct {
+		input  string
+		output string
+	}{
+		{input: "none /mnt/data -o config-file=/etc/bf.yaml",
+			output: "mount /mnt/data --config-file=/etc/bf.yaml"},
+		{input: "data /mnt/data -o rw,allow_other,uid=1000,config-file=/etc/bf.yaml,read-only",
+			output: "mount /mnt/data -o rw,allow_other,uid=1000 --container-name=data --config-file=/etc/bf.yaml --read-only=true"},
+		{input: "data /mnt/data -o container-name=other,secure-config,passphrase=abc",
+			output: "mount /mnt/data --container-name=other --secure-config=true --passphrase=abc"},
+		{input: "blobfuse2 /mnt/data -n -o _netdev,nofail,x-systemd.automount,--config-file=/etc/bf.yaml,env-file=/etc/bf.env",
+			output: "mount /mnt/data -o _netdev,nofail,x-systemd.automount --config-file=/etc/bf.yaml --env-file=/etc/bf.env"},
+		{input: "none /mnt/data -r -t blobfuse2 -o config-file=bf.yaml",
+			output: "mount /mnt/data -o ro --config-file=bf.yaml"},
+		{input: "none /mnt/data -s -o config-file=bf.yaml,bogus=1",
+			output: "mount /mnt/data --config-file=bf.yaml"},
+	}
+
+	for _, i := range inputs {
+		args, fake, err := helperArgs(strings.Split("mount.blobfuse2 "+i.input, " "))
+		suite.assert.Nil(err, i.input)
+		suite.assert.False(fake)
+		suite.assert.Equal(i.output, strings.Join(args, " "))
+	}
+
+	_, fake, err := helperArgs(strings.Split("mount.blobfuse2 none /mnt/data -f -o config-file=bf.yaml", " "))
+	suite.assert.Nil(err)
+	suite.assert.True(fake)
+}
+
+func (suite *mountHelperTestSuite) TestHelperArgsInvalid() {
+	var inputs = []struct {
+		input string
+		err   string
+	}{
+		{input: "/mnt/data", err: "usage"},
+		{input: "none /mnt/data -o bogus=1", err: "unknown option bogus"},
+		{input: "none /mnt/data -o config-file", err: "option config-file requires a value"},
+		{input: "none /mnt/data -x", err: "unknown option -x"},
+		{input: "none /mnt/data -o", err: "option -o requires a value"},
+	}
+
+	for _, i := range inputs {
+		_, _, err := helperArgs(strings.Split("mount.blobfuse2 "+i.input, " "))
+		suite.assert.NotNil(err, i.input)
+		suite.assert.Contains(err.Error(), i.err)
+	}
+}
+
+func (suite *mountHelperTestSuite) TestLoadEnvFile() {
+	path := filepath.Join(suite.T().TempDir(), "blobfuse2.env")
+	content := "# credentials\n\nAZURE_STORAGE_ACCOUNT=myaccount\nexport AZURE_STORAGE_ACCESS_KEY=\"a=b\"\nBLOBFUSE2_TEST_EMPTY=\n"
+	suite.assert.Nil(os.WriteFile(path, []byte(content), 0600))
+	defer os.Unsetenv("AZURE_STORAGE_ACCOUNT")
+	defer os.Unsetenv("AZURE_STORAGE_ACCESS_KEY")
+	defer os.Unsetenv("BLOBFUSE2_TEST_EMPTY")
+
+	suite.assert.Nil(loadEnvFile(path))
+	suite.assert.Equal("myaccount", os.Getenv("AZURE_STORAGE_ACCOUNT"))
+	suite.assert.Equal("a=b", os.Getenv("AZURE_STORAGE_ACCESS_KEY"))
+	_, ok := os.LookupEnv("BLOBFUSE2_TEST_EMPTY")
+	suite.assert.True(ok)
+
+	suite.assert.Nil(os.WriteFile(path, []byte("not a variable\n"), 0600))
+	err := loadEnvFile(path)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "line 1")
+
+	suite.assert.NotNil(loadEnvFile(filepath.Join(suite.T().TempDir(), "missing")))
+}
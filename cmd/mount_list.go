@@ -34,30 +34,195 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
-	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
 
 	"github.com/spf13/cobra"
 )
 
+type mountListOptions struct {
+	JSON bool
+}
+
+var mountListOpts mountListOptions
+
+// Time a mount gets to report its status before it is taken as unresponsive
+const mountStatusTimeout = 5 * time.Second
+
+// mountInfo : State of a mount as listed with --json
+type mountInfo struct {
+	MountPath    string   `json:"mountPath"`
+	Health       string   `json:"health"`
+	Reasons      []string `json:"reasons,omitempty"`
+	PID          int      `json:"pid,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	ConfigFile   string   `json:"configFile,omitempty"`
+	Account      string   `json:"account,omitempty"`
+	Container    string   `json:"container,omitempty"`
+	StartTime    string   `json:"startTime,omitempty"`
+	UptimeSec    int64    `json:"uptimeSec,omitempty"`
+	CachePath    string   `json:"cachePath,omitempty"`
+	CacheUsageMB float64  `json:"cacheUsageMB,omitempty"`
+	CacheMaxMB   float64  `json:"cacheMaxMB,omitempty"`
+	Restarts     int      `json:"restarts,omitempty"`
+	PointInTime  string   `json:"pointInTime,omitempty"`
+
+	// Set for the named mounts of a blobfuse2 service
+	Name         string `json:"name,omitempty"`
+	ServiceState string `json:"serviceState,omitempty"`
+}
+
+// listedMount : Mount point along with the named mount of the service owning it, if any
+type listedMount struct {
+	path  string
+	named *admin.MountState
+}
+
 var mountListCmd = &cobra.Command{
 	Use:               "list",
 	Short:             "List all blobfuse2 mountpoints",
-	Long:              "List all blobfuse2 mountpoints",
+	Long:              "List all blobfuse2 mountpoints. With --json the pid, account and container, uptime, config file, cache usage and health of each mount are reported for scripts and monitoring agents to consume. A mount is healthy, degraded when storage is failing or unresponsive when it does not answer. Named mounts of a running blobfuse2 service are listed with their name and state, along with the ones which failed.",
 	SuggestFor:        []string{"lst", "list"},
-	Example:           "blobfuse2 mount list",
+	Example:           "blobfuse2 mount list\nblobfuse2 mount list --json",
 	FlagErrorHandling: cobra.ExitOnError,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		lstMnt, err := common.ListMountPoints()
+		lstMnt, err := listMountPoints()
 		if err != nil {
 			return fmt.Errorf("failed to list mount points [%s]", err.Error())
 		}
 
-		for i, mntPath := range lstMnt {
-			fmt.Println(i+1, ":", mntPath)
+		// A service which is not running simply owns no mounts
+		var named []admin.MountState
+		resp, err := admin.SendTimeout(admin.ServiceAddress(mountServiceOpts.Service), admin.Request{Verb: admin.VerbMountList}, mountStatusTimeout)
+		if err == nil {
+			named = resp.Mounts
+		}
+		mounts := mergeNamedMounts(lstMnt, named)
+
+		if mountListOpts.JSON {
+			infos := make([]mountInfo, 0, len(mounts))
+			for _, m := range mounts {
+				infos = append(infos, m.info(time.Now()))
+			}
+
+			data, err := json.MarshalIndent(infos, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		for i, m := range mounts {
+			switch {
+			case m.named == nil:
+				fmt.Println(i+1, ":", m.path)
+			case m.named.Reason != "":
+				fmt.Printf("%d : %s (%s, %s: %s)\n", i+1, m.path, m.named.Name, m.named.State, m.named.Reason)
+			default:
+				fmt.Printf("%d : %s (%s, %s)\n", i+1, m.path, m.named.Name, m.named.State)
+			}
 		}
 
 		return nil
 	},
 }
+
+// mergeNamedMounts : Mount points in the order they were mounted, followed by the named mounts which are not mounted
+func mergeNamedMounts(paths []string, named []admin.MountState) []listedMount {
+	byPath := make(map[string]*admin.MountState, len(named))
+	for i := range named {
+		byPath[named[i].MountPath] = &named[i]
+	}
+
+	mounts := make([]listedMount, 0, len(paths)+len(named))
+	for _, path := range paths {
+		m := listedMount{path: path, named: byPath[path]}
+		delete(byPath, path)
+		mounts = append(mounts, m)
+	}
+
+	for i := range named {
+		if _, found := byPath[named[i].MountPath]; found {
+			mounts = append(mounts, listedMount{path: named[i].MountPath, named: &named[i]})
+		}
+	}
+	return mounts
+}
+
+// info : State of the mount, a named mount which failed is not asked for it
+func (m listedMount) info(now time.Time) mountInfo {
+	var info mountInfo
+	if m.named != nil && m.named.State == admin.MountFailed {
+		info = mountInfo{MountPath: m.path, Health: admin.HealthUnresponsive, Reasons: []string{m.named.Reason}}
+	} else {
+		info = getMountInfo(m.path, now)
+	}
+
+	if m.named != nil {
+		info.Name = m.named.Name
+		info.ServiceState = m.named.State
+	}
+	return info
+}
+
+// getMountInfo : Collect the status the components of a mount report, a mount which does not answer is unresponsive
+func getMountInfo(mountPath string, now time.Time) mountInfo {
+	info := mountInfo{MountPath: mountPath, Health: admin.HealthHealthy}
+
+	resp, err := admin.SendTimeout(mountPath, admin.Request{Verb: admin.VerbStatus}, mountStatusTimeout)
+	if err != nil {
+		info.Health = admin.HealthUnresponsive
+		info.Reasons = []string{err.Error()}
+		return info
+	}
+
+	for _, component := range sortedKeys(resp.Status) {
+		status := resp.Status[component]
+		for key, value := range status {
+			switch key {
+			case admin.StatusPID:
+				info.PID, _ = strconv.Atoi(value)
+			case admin.StatusVersion:
+				info.Version = value
+			case admin.StatusConfigFile:
+				info.ConfigFile = value
+			case admin.StatusAccount:
+				info.Account = value
+			case admin.StatusContainer:
+				info.Container = value
+			case admin.StatusStartTime:
+				info.StartTime = value
+				if start, err := time.Parse(time.RFC3339, value); err == nil {
+					info.UptimeSec = int64(now.Sub(start).Seconds())
+				}
+			case admin.StatusCachePath:
+				info.CachePath = value
+			case admin.StatusCacheUsageMB:
+				info.CacheUsageMB, _ = strconv.ParseFloat(value, 64)
+			case admin.StatusCacheMaxMB:
+				info.CacheMaxMB, _ = strconv.ParseFloat(value, 64)
+			case admin.StatusRestarts:
+				info.Restarts, _ = strconv.Atoi(value)
+			case admin.StatusPointInTime:
+				info.PointInTime = value
+			}
+		}
+
+		if status[admin.StatusHealth] == admin.HealthDegraded {
+			info.Health = admin.HealthDegraded
+			info.Reasons = append(info.Reasons, fmt.Sprintf("%s: %s", component, status[admin.StatusReason]))
+		}
+	}
+
+	return info
+}
+
+func init() {
+	mountListCmd.Flags().BoolVar(&mountListOpts.JSON, "json", false,
+		"Report pid, container, uptime, config file, cache usage and health of each mount in JSON format")
+}
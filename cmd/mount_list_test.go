@@ -0,0 +1,137 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type mountListTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *mountListTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *mountListTestSuite) cleanupTest() {
+	mountListOpts = mountListOptions{}
+	listMountPoints = common.ListMountPoints
+	mountListCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestMountListCommand(t *testing.T) {
+	suite.Run(t, new(mountListTestSuite))
+}
+
+func (suite *mountListTestSuite) TestMountListJSON() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	_, err := executeCommandC(rootCmd, "mount", "list", "--json")
+	suite.assert.Nil(err)
+}
+
+func (suite *mountListTestSuite) TestGetMountInfo() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	start := time.Now().Add(-time.Minute)
+	admin.RegisterStatus("mount", func() map[string]string {
+		return map[string]string{
+			admin.StatusPID:        "1234",
+			admin.StatusConfigFile: "/etc/blobfuse2.yaml",
+			admin.StatusStartTime:  start.Format(time.RFC3339),
+		}
+	})
+	defer admin.UnregisterStatus("mount")
+	admin.RegisterStatus("azstorage", func() map[string]string {
+		return map[string]string{
+			admin.StatusContainer: "data",
+			admin.StatusHealth:    admin.HealthDegraded,
+			admin.StatusReason:    "last storage request failed [errors_503]",
+		}
+	})
+	defer admin.UnregisterStatus("azstorage")
+	admin.RegisterStatus("file_cache", func() map[string]string {
+		return map[string]string{admin.StatusCachePath: "/tmp/cache", admin.StatusCacheUsageMB: "12.50"}
+	})
+	defer admin.UnregisterStatus("file_cache")
+
+	mountPath := suite.T().TempDir()
+
+	// Nothing answers on the socket of the mount
+	info := getMountInfo(mountPath, time.Now())
+	suite.assert.Equal(admin.HealthUnresponsive, info.Health)
+	suite.assert.Len(info.Reasons, 1)
+
+	err := admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	info = getMountInfo(mountPath, time.Now())
+	suite.assert.Equal(mountPath, info.MountPath)
+	suite.assert.Equal(1234, info.PID)
+	suite.assert.Equal("/etc/blobfuse2.yaml", info.ConfigFile)
+	suite.assert.Equal("data", info.Container)
+	suite.assert.InDelta(60, info.UptimeSec, 2)
+	suite.assert.Equal("/tmp/cache", info.CachePath)
+	suite.assert.EqualValues(12.5, info.CacheUsageMB)
+	suite.assert.Equal(admin.HealthDegraded, info.Health)
+	suite.assert.Equal([]string{"azstorage: last storage request failed [errors_503]"}, info.Reasons)
+}
@@ -0,0 +1,137 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+
+	"github.com/spf13/cobra"
+)
+
+type mountSnapshotOptions struct {
+	At string
+}
+
+var mountSnapshotOpts mountSnapshotOptions
+
+// Time a snapshot mount presents the container as of, zero for any other mount
+var snapshotAt time.Time
+
+// Layouts --at of mount snapshot is accepted in, times without a zone are in UTC
+var snapshotTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// Settings a snapshot mount overrides, so nothing is written and no cache is shared with a mount of the container as it
+// is now
+var snapshotOverrides = map[string]string{
+	"read-only":                     "true",
+	"file_cache.write-back":         "false",
+	"file_cache.async-close":        "false",
+	"file_cache.pipelined-upload":   "false",
+	"file_cache.offline-access":     "false",
+	"file_cache.persist-cache":      "false",
+	"file_cache.shared-cache":       "false",
+	"file_cache.crash-recovery":     "",
+	"file_cache.reconcile-on-start": "",
+	"file_cache.cache-image":        "",
+	"file_cache.warm-manifest":      "",
+	"file_cache.cleanup-on-start":   "true",
+	"attr_cache.shared-cache-path":  "",
+	"attr_cache.snapshot-path":      "",
+	"attr_cache.inventory-file":     "",
+	"attr_cache.coherence-events":   "false",
+}
+
+var mountSnapshotCmd = &cobra.Command{
+	Use:               "snapshot <mount path> --at <time>",
+	Short:             "Mounts the azure container read-only as it was at a point in time",
+	Long:              "Mounts the azure container read-only as it was at a point in time, reading each blob from its latest version or snapshot taken at or before that time. Blob versioning or snapshots have to be enabled on the account. Writes, uploads and caches shared with other mounts are turned off, and the file cache is kept in a directory of its own under the configured one. All other flags of mount apply.",
+	SuggestFor:        []string{"snapshots", "snap"},
+	Example:           "blobfuse2 mount snapshot ~/inspect --at=2026-10-01T09:00:00Z --config-file=config.yaml\nblobfuse2 mount snapshot ~/inspect --at=2026-10-01",
+	Args:              usageArgs(cobra.ExactArgs(1)),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		at, err := parseSnapshotTime(mountSnapshotOpts.At, time.Now())
+		if err != nil {
+			return withExitCode(exitUsage, err)
+		}
+
+		snapshotAt = at
+		return mountCmd.RunE(cmd, args)
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveDefault
+	},
+}
+
+// parseSnapshotTime : Point in time given to mount snapshot, in UTC
+func parseSnapshotTime(value string, now time.Time) (time.Time, error) {
+	for _, layout := range snapshotTimeLayouts {
+		at, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+
+		if at.After(now) {
+			return time.Time{}, fmt.Errorf("time %s is in the future", value)
+		}
+		return at.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s, it shall be like 2006-01-02T15:04:05Z, 2006-01-02T15:04:05 or 2006-01-02", value)
+}
+
+// applySnapshotMode : Read blobs as they were at the time, on a read-only mount with every write path of the caches off
+func applySnapshotMode(at time.Time) {
+	config.Set("azstorage.point-in-time", at.Format(time.RFC3339))
+	for key, value := range snapshotOverrides {
+		config.Set(key, value)
+	}
+
+	// Files of the container as it is now shall not be taken for the ones as they were
+	cachePath := ""
+	_ = config.UnmarshalKey("file_cache.path", &cachePath)
+	if cachePath != "" {
+		config.Set("file_cache.path", filepath.Join(cachePath, "snapshot-"+at.Format("20060102T150405Z")))
+	}
+}
+
+func init() {
+	mountSnapshotCmd.Flags().StringVar(&mountSnapshotOpts.At, "at", "",
+		"Time to present the container as of, e.g. 2026-10-01T09:00:00Z. Times without a zone are in UTC.")
+	_ = mountSnapshotCmd.MarkFlagRequired("at")
+	_ = mountSnapshotCmd.RegisterFlagCompletionFunc("at", cobra.NoFileCompletions)
+}
@@ -0,0 +1,145 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type mountSnapshotTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *mountSnapshotTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	options = mountOptions{}
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *mountSnapshotTestSuite) cleanupTest() {
+	resetCLIFlags(*mountCmd)
+	resetCLIFlags(*mountSnapshotCmd)
+	mountSnapshotOpts = mountSnapshotOptions{}
+	snapshotAt = time.Time{}
+	viper.Reset()
+}
+
+func TestMountSnapshot(t *testing.T) {
+	suite.Run(t, new(mountSnapshotTestSuite))
+}
+
+func (suite *mountSnapshotTestSuite) TestParseSnapshotTime() {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	at, err := parseSnapshotTime("2026-10-01T11:00:00+02:00", now)
+	suite.assert.Nil(err)
+	suite.assert.Equal(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), at)
+
+	at, err = parseSnapshotTime("2026-10-01T09:30:00", now)
+	suite.assert.Nil(err)
+	suite.assert.Equal(time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC), at)
+
+	at, err = parseSnapshotTime("2026-10-01", now)
+	suite.assert.Nil(err)
+	suite.assert.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), at)
+
+	_, err = parseSnapshotTime("2026-10-19", now)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "is in the future")
+
+	_, err = parseSnapshotTime("yesterday", now)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "invalid time yesterday")
+}
+
+func (suite *mountSnapshotTestSuite) TestApplySnapshotMode() {
+	defer suite.cleanupTest()
+
+	viper.SetConfigType("yaml")
+	err := config.ReadFromConfigBuffer([]byte("file_cache:\n  path: /tmp/cache\n  write-back: true\n  shared-cache: true\nattr_cache:\n  snapshot-path: /tmp/attr.snap\n"))
+	suite.assert.Nil(err)
+
+	applySnapshotMode(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))
+
+	pointInTime := ""
+	suite.assert.Nil(config.UnmarshalKey("azstorage.point-in-time", &pointInTime))
+	suite.assert.Equal("2026-10-01T09:00:00Z", pointInTime)
+
+	readOnly := false
+	suite.assert.Nil(config.UnmarshalKey("read-only", &readOnly))
+	suite.assert.True(readOnly)
+
+	fileCache := struct {
+		Path           string `config:"path"`
+		WriteBack      bool   `config:"write-back"`
+		SharedCache    bool   `config:"shared-cache"`
+		CleanupOnStart bool   `config:"cleanup-on-start"`
+	}{}
+	suite.assert.Nil(config.UnmarshalKey("file_cache", &fileCache))
+	suite.assert.Equal(filepath.Join("/tmp/cache", "snapshot-20261001T090000Z"), fileCache.Path)
+	suite.assert.False(fileCache.WriteBack)
+	suite.assert.False(fileCache.SharedCache)
+	suite.assert.True(fileCache.CleanupOnStart)
+
+	snapshotPath := "unset"
+	suite.assert.Nil(config.UnmarshalKey("attr_cache.snapshot-path", &snapshotPath))
+	suite.assert.Empty(snapshotPath)
+}
+
+func (suite *mountSnapshotTestSuite) TestMountSnapshotInvalidTime() {
+	defer suite.cleanupTest()
+
+	mntDir, err := os.MkdirTemp("", "mntdir")
+	suite.assert.Nil(err)
+	defer os.RemoveAll(mntDir)
+
+	op, err := executeCommandC(rootCmd, "mount", "snapshot", mntDir, "--at=yesterday")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid time yesterday")
+}
@@ -295,6 +295,50 @@ func (suite *mountTestSuite) TestInvalidLogLevel() {
 	suite.assert.Contains(op, "invalid log level")
 }
 
+func (suite *mountTestSuite) TestInvalidLogFormat() {
+	defer suite.cleanupTest()
+
+	mntDir, err := os.MkdirTemp("", "mntdir")
+	suite.assert.Nil(err)
+	defer os.RemoveAll(mntDir)
+
+	op, err := executeCommandC(rootCmd, "mount", mntDir, fmt.Sprintf("--config-file=%s", confFileMntTest), "--log-format=xml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid log format xml")
+}
+
+func (suite *mountTestSuite) TestInvalidShutdownCache() {
+	defer suite.cleanupTest()
+
+	mntDir, err := os.MkdirTemp("", "mntdir")
+	suite.assert.Nil(err)
+	defer os.RemoveAll(mntDir)
+
+	op, err := executeCommandC(rootCmd, "mount", mntDir, fmt.Sprintf("--config-file=%s", confFileMntTest), "--shutdown-cache=keep")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid shutdown-cache keep")
+}
+
+func (suite *mountTestSuite) TestInvalidTracingEndpoint() {
+	defer suite.cleanupTest()
+
+	mntDir, err := os.MkdirTemp("", "mntdir")
+	suite.assert.Nil(err)
+	defer os.RemoveAll(mntDir)
+
+	confFile, err := os.CreateTemp("", "conf*.yaml")
+	suite.assert.Nil(err)
+	defer os.Remove(confFile.Name())
+
+	_, err = confFile.WriteString(configMountTest + "tracing:\n  endpoint: collector:4318\n")
+	suite.assert.Nil(err)
+	confFile.Close()
+
+	op, err := executeCommandC(rootCmd, "mount", mntDir, fmt.Sprintf("--config-file=%s", confFile.Name()))
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid tracing endpoint collector:4318")
+}
+
 func (suite *mountTestSuite) TestCliParamsV1() {
 	defer suite.cleanupTest()
 
@@ -398,6 +442,10 @@ func (suite *mountTestSuite) TestFuseOptions() {
 		{opt: "nouser", ignore: true},
 		{opt: "exec", ignore: true},
 		{opt: "noexec", ignore: true},
+		{opt: "defaults", ignore: true},
+		{opt: "_netdev", ignore: true},
+		{opt: "nofail", ignore: true},
+		{opt: "x-systemd.automount", ignore: true},
 
 		{opt: "allow_other", ignore: false},
 		{opt: "allow_other=true", ignore: false},
@@ -0,0 +1,65 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// mountInBackground : Windows has no daemons, run blobfuse2 in foreground, e.g. as a service, instead
+func mountInBackground(run func(ctx context.Context) error) error {
+	return Destroy("mounting in background is not supported on Windows, pass --foreground=true")
+}
+
+// unmountDirect : WinFsp mounts last as long as the process serving them
+func unmountDirect(mntPath string, lazy bool, force bool) error {
+	return errors.New("stop the blobfuse2 process serving " + mntPath + " to unmount it")
+}
+
+// syslogWarning : There is no syslog on Windows, warn on stderr instead
+func syslogWarning(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+// validateMountPath : WinFsp creates the mount point itself, so it has to be a free drive letter like X: or a path not existing yet
+func validateMountPath(mntPath string, skipEmptyMount bool) error {
+	if _, err := os.Stat(mntPath); err == nil {
+		return withExitCode(exitMountPath, fmt.Errorf("mount path already exists, use a free drive letter or a path not existing yet"))
+	}
+
+	return nil
+}
@@ -38,7 +38,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"log/syslog"
 	"os"
 	"strconv"
 	"strings"
@@ -64,7 +63,7 @@ type blobfuseCliOptions struct {
 	useStreaming bool
 	// fuseAttrTimeout   uint32
 	// fuseEntryTimeout  uint32
-	tmpPath           string
+	tmpPaths          []string
 	cacheSize         float64
 	fileCacheTimeout  uint32
 	maxEviciton       uint32
@@ -122,6 +121,8 @@ var useAttrCache bool
 var useStream bool
 var useFileCache bool = true
 var convertConfigOnly bool
+var convertFormat string
+var printMigrationReport bool
 var enableGen1 bool
 var reqFreeSpaceMB int
 
@@ -140,6 +141,7 @@ func resetOptions() {
 	useAttrCache = false
 	useStream = false
 	useFileCache = true
+	migration = migrationReport{}
 }
 
 var generateConfigCmd = &cobra.Command{
@@ -157,6 +159,13 @@ var generateConfigCmd = &cobra.Command{
 			}
 		}
 		resetOptions()
+		if convertFormat != convertFormatYAML && convertFormat != convertFormatFlags {
+			return withExitCode(exitUsage, fmt.Errorf("invalid output format %s, allowed values are %s|%s", convertFormat, convertFormatYAML, convertFormatFlags))
+		}
+		if convertFormat == convertFormatFlags && !convertConfigOnly {
+			return withExitCode(exitUsage, fmt.Errorf("output format %s needs --convert-config-only", convertFormatFlags))
+		}
+
 		// If we are only converting the config without mounting then we do not need the mount path and therefore the args length would be 0
 		if len(args) == 1 {
 			mountPath = args[0]
@@ -253,10 +262,32 @@ var generateConfigCmd = &cobra.Command{
 			bfv2StorageConfigOptions,
 			bfv2ComponentsConfigOptions}
 
-		data, _ := yaml.Marshal(&pConf)
-		err2 := os.WriteFile(outputFilePath, data, 0700)
-		if err2 != nil {
-			return fmt.Errorf("failed to write file [%s]", err2.Error())
+		tree, settings, err := convertedSettings(&pConf)
+		if err != nil {
+			return fmt.Errorf("failed to generate configuration [%s]", err.Error())
+		}
+		validateConverted(tree)
+
+		if convertFormat == convertFormatFlags {
+			line := mountCommandLine(mountPath, settings) + "\n"
+			if cmd.Flags().Lookup("output-file").Changed {
+				err = os.WriteFile(outputFilePath, []byte(line), 0700)
+			} else {
+				_, err = fmt.Fprint(cmd.OutOrStdout(), line)
+			}
+		} else {
+			data, _ := yaml.Marshal(&pConf)
+			err = os.WriteFile(outputFilePath, data, 0700)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write file [%s]", err.Error())
+		}
+
+		reportAuthEnv(settings, convertFormat)
+		if printMigrationReport {
+			migration.print(cmd.OutOrStdout())
+		} else if len(migration.Manual) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%d blobfuse options need manual attention, rerun with --report to list them\n", len(migration.Manual))
 		}
 
 		if !convertConfigOnly {
@@ -292,35 +323,44 @@ func parseFuseConfig(config []string) error {
 
 		v = strings.TrimSpace(v)
 		if ignoreFuseOptions(v) {
+			migration.dropped("-o "+v, "ignored by Blobfuse2")
 			continue
 		} else if v == "allow_other" || v == "allow_other=true" {
 			bfv2AllowOtherOption = true
+			migration.mapped("-o "+v, "allow-other")
 		} else if v == "allow_other=false" {
 			bfv2AllowOtherOption = false
+			migration.mapped("-o "+v, "allow-other")
 		} else if v == "nonempty" {
 			bfv2NonEmptyMountOption = true
+			migration.mapped("-o "+v, "nonempty")
 		} else if strings.HasPrefix(v, "attr_timeout=") {
 			timeout, err := strconv.ParseUint(parameter[1], 10, 32)
 			if err != nil {
 				return fmt.Errorf("failed to parse attr_timeout [%s]", err.Error())
 			}
 			bfv2FuseConfigOptions.AttributeExpiration = uint32(timeout)
+			migration.mapped("-o attr_timeout", "libfuse.attribute-expiration-sec")
 		} else if strings.HasPrefix(v, "entry_timeout=") {
 			timeout, err := strconv.ParseUint(parameter[1], 10, 32)
 			if err != nil {
 				return fmt.Errorf("failed to parse entry_timeout [%s]", err.Error())
 			}
 			bfv2FuseConfigOptions.EntryExpiration = uint32(timeout)
+			migration.mapped("-o entry_timeout", "libfuse.entry-expiration-sec")
 		} else if strings.HasPrefix(v, "negative_timeout=") {
 			timeout, err := strconv.ParseUint(parameter[1], 10, 32)
 			if err != nil {
 				return fmt.Errorf("failed to parse negative_timeout [%s]", err.Error())
 			}
 			bfv2FuseConfigOptions.NegativeEntryExpiration = uint32(timeout)
+			migration.mapped("-o negative_timeout", "libfuse.negative-entry-expiration-sec")
 		} else if v == "ro" {
 			bfv2ReadOnlyOption = true
+			migration.mapped("-o ro", "read-only")
 		} else if v == "allow_root" {
 			bfv2FuseConfigOptions.DefaultPermission = 700
+			migration.mapped("-o allow_root", "libfuse.default-permission")
 		} else if strings.HasPrefix(v, "umask=") {
 			permission, err := strconv.ParseUint(parameter[1], 10, 32)
 			if err != nil {
@@ -328,6 +368,7 @@ func parseFuseConfig(config []string) error {
 			}
 			perm := ^uint32(permission) & 777
 			bfv2FuseConfigOptions.DefaultPermission = perm
+			migration.mapped("-o umask", "libfuse.default-permission")
 		} else {
 			return errors.New(common.FuseAllowedFlags)
 		}
@@ -338,52 +379,78 @@ func parseFuseConfig(config []string) error {
 
 // helper method: converts config file options
 func convertBfConfigParameter(flags *pflag.FlagSet, configParameterKey string, configParameterValue string) error {
+	// options of the config file that a cli option supersedes
+	overriddenBy := map[string]string{
+		"logLevel":      "log-level",
+		"accountType":   "use-adls",
+		"containerName": "container-name",
+		"httpProxy":     "http-proxy",
+	}
+	if flag, ok := overriddenBy[configParameterKey]; ok && flags.Lookup(flag).Changed {
+		migration.dropped(configParameterKey, fmt.Sprintf("overridden by --%s", flag))
+		return nil
+	}
+
 	switch configParameterKey {
 	case "logLevel":
-		if !flags.Lookup("log-level").Changed {
-			bfv2LoggingConfigOptions.LogLevel = configParameterValue
-		}
+		bfv2LoggingConfigOptions.LogLevel = configParameterValue
+		migration.mapped(configParameterKey, "logging.level")
 	case "accountName":
 		bfv2StorageConfigOptions.AccountName = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.account-name")
 	case "accountKey":
 		bfv2StorageConfigOptions.AccountKey = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.account-key")
 	case "accountType":
-		if !flags.Lookup("use-adls").Changed {
-			bfv2StorageConfigOptions.AccountType = configParameterValue
-		}
+		bfv2StorageConfigOptions.AccountType = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.type")
 	case "aadEndpoint":
 		bfv2StorageConfigOptions.ActiveDirectoryEndpoint = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.aadendpoint")
 	case "authType":
 		bfv2StorageConfigOptions.AuthMode = strings.ToLower(configParameterValue)
+		migration.mapped(configParameterKey, "azstorage.mode")
 	case "blobEndpoint":
 		bfv2StorageConfigOptions.Endpoint = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.endpoint")
 	case "containerName":
-		if !flags.Lookup("container-name").Changed {
-			bfv2StorageConfigOptions.Container = configParameterValue
-		}
+		bfv2StorageConfigOptions.Container = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.container")
 	case "httpProxy":
-		if !flags.Lookup("http-proxy").Changed {
-			bfv2StorageConfigOptions.HttpProxyAddress = configParameterValue
-		}
+		bfv2StorageConfigOptions.HttpProxyAddress = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.http-proxy")
 	case "identityClientId":
 		bfv2StorageConfigOptions.ApplicationID = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.appid")
 	case "httpsProxy":
 		bfv2StorageConfigOptions.HttpsProxyAddress = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.https-proxy")
 	case "identityObjectId":
 		bfv2StorageConfigOptions.ObjectID = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.objid")
 	case "identityResourceId":
 		bfv2StorageConfigOptions.ResourceID = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.resid")
 	case "sasToken":
 		bfv2StorageConfigOptions.SaSKey = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.sas")
 	case "servicePrincipalClientId":
 		bfv2StorageConfigOptions.ClientID = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.clientid")
 	case "servicePrincipalClientSecret":
 		bfv2StorageConfigOptions.ClientSecret = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.clientsecret")
 	case "servicePrincipalTenantId":
 		bfv2StorageConfigOptions.TenantID = configParameterValue
+		migration.mapped(configParameterKey, "azstorage.tenantid")
 
 	case "msiEndpoint":
 		// msiEndpoint is not supported config in V2, this needs to be given as MSI_ENDPOINT env variable
+		if os.Getenv("MSI_ENDPOINT") != configParameterValue {
+			migration.manual(configParameterKey, "MSI_ENDPOINT", "not a config option in Blobfuse2, export MSI_ENDPOINT instead")
+		} else {
+			migration.dropped(configParameterKey, "MSI_ENDPOINT is already exported with the same value")
+		}
 		return nil
 
 	default:
@@ -395,28 +462,55 @@ func convertBfConfigParameter(flags *pflag.FlagSet, configParameterKey string, c
 
 // helper method: converts cli options - cli options that overlap with config file take precedence
 func convertBfCliParameters(flags *pflag.FlagSet) error {
-	if flags.Lookup("set-content-type").Changed || flags.Lookup("ca-cert-file").Changed || flags.Lookup("basic-remount-check").Changed || flags.Lookup(
-		"background-download").Changed || flags.Lookup("cache-poll-timeout-msec").Changed || flags.Lookup("upload-modified-only").Changed || flags.Lookup("debug-libcurl").Changed {
-		logWriter, _ := syslog.New(syslog.LOG_WARNING, "")
-		_ = logWriter.Warning("one or more unsupported v1 parameters [set-content-type, ca-cert-file, basic-remount-check, background-download, cache-poll-timeout-msec, upload-modified-only, debug-libcurl] have been passed, ignoring and proceeding to mount")
+	unsupported := []string{"set-content-type", "ca-cert-file", "basic-remount-check", "background-download", "cache-poll-timeout-msec", "upload-modified-only", "debug-libcurl"}
+	passed := false
+	for _, name := range unsupported {
+		if flags.Lookup(name).Changed {
+			migration.dropped("--"+name, "not supported in Blobfuse2")
+			passed = true
+		}
+	}
+	if passed {
+		syslogWarning("one or more unsupported v1 parameters [set-content-type, ca-cert-file, basic-remount-check, background-download, cache-poll-timeout-msec, upload-modified-only, debug-libcurl] have been passed, ignoring and proceeding to mount")
+	}
+	for _, name := range []string{"invalidate-on-sync", "pre-mount-validate"} {
+		if flags.Lookup(name).Changed {
+			migration.dropped("--"+name, "always on in Blobfuse2")
+		}
+	}
+
+	// cliOption : record the blobfuse2 setting a cli option that was passed maps to
+	cliOption := func(name string, target string) bool {
+		if !flags.Lookup(name).Changed {
+			return false
+		}
+		migration.mapped("--"+name, target)
+		return true
+	}
+	// unusedOption : record a cli option that was passed but has no effect without another one
+	unusedOption := func(name string, note string) {
+		if flags.Lookup(name).Changed {
+			migration.dropped("--"+name, note)
+		}
 	}
 
 	bfv2LoggingConfigOptions.Type = "syslog"
-	if flags.Lookup("log-level").Changed {
+	if cliOption("log-level", "logging.level") {
 		bfv2LoggingConfigOptions.LogLevel = bfConfCliOptions.logLevel
 	}
 
-	if flags.Lookup("streaming").Changed {
+	streamOptions := []string{"block-size-mb", "max-blocks-per-file", "stream-cache-mb"}
+	if cliOption("streaming", "components") {
 		if bfConfCliOptions.useStreaming {
 			useStream = true
 			useFileCache = false
-			if flags.Lookup("block-size-mb").Changed {
+			if cliOption("block-size-mb", "stream.block-size-mb") {
 				bfv2StreamConfigOptions.BlockSize = bfConfCliOptions.blockSize
 			}
-			if flags.Lookup("max-blocks-per-file").Changed {
+			if cliOption("max-blocks-per-file", "stream.buffer-size-mb") {
 				bfv2StreamConfigOptions.BufferSize = bfConfCliOptions.blockSize * uint64(bfConfCliOptions.maxBlocksPerFile)
 			}
-			if flags.Lookup("stream-cache-mb").Changed {
+			if cliOption("stream-cache-mb", "stream.max-buffers") {
 				bfv2StreamConfigOptions.CachedObjLimit = bfConfCliOptions.streamCacheSize / bfv2StreamConfigOptions.BufferSize
 				if bfv2StreamConfigOptions.CachedObjLimit == 0 {
 					bfv2StreamConfigOptions.CachedObjLimit = 1
@@ -425,26 +519,66 @@ func convertBfCliParameters(flags *pflag.FlagSet) error {
 		} else {
 			useStream = false
 			useFileCache = true
+			for _, name := range streamOptions {
+				unusedOption(name, "only used with --streaming")
+			}
+		}
+	} else {
+		for _, name := range streamOptions {
+			unusedOption(name, "only used with --streaming")
 		}
 	}
 
-	if flags.Lookup("use-attr-cache").Changed {
+	if cliOption("use-attr-cache", "components") {
 		useAttrCache = true
 		if bfConfCliOptions.useAttrCache {
-			if flags.Lookup("cache-on-list").Changed {
+			if cliOption("cache-on-list", "attr_cache.no-cache-on-list") {
 				if bfConfCliOptions.cacheOnList {
 					bfv2AttrCacheConfigOptions.NoCacheOnList = !bfConfCliOptions.cacheOnList
 				}
 			}
-			if flags.Lookup("no-symlinks").Changed {
+			if cliOption("no-symlinks", "attr_cache.no-symlinks") {
 				if bfConfCliOptions.noSymlinks {
 					bfv2AttrCacheConfigOptions.NoSymlinks = bfConfCliOptions.noSymlinks
 				}
 			}
 		}
 	}
+	if !bfConfCliOptions.useAttrCache {
+		unusedOption("cache-on-list", "only used with --use-attr-cache")
+		unusedOption("no-symlinks", "only used with --use-attr-cache")
+	}
+
+	fileCacheOptions := []struct {
+		name   string
+		target string
+	}{
+		{"tmp-path", "file_cache.path"},
+		{"cache-size-mb", "file_cache.max-size-mb"},
+		{"file-cache-timeout-in-seconds", "file_cache.timeout-sec"},
+		{"max-eviction", "file_cache.max-eviction"},
+		{"high-disk-threshold", "file_cache.high-threshold"},
+		{"low-disk-threshold", "file_cache.low-threshold"},
+		{"empty-dir-check", "file_cache.allow-non-empty-temp"},
+	}
+	if !useFileCache {
+		for _, o := range fileCacheOptions {
+			unusedOption(o.name, "file cache is not used with --streaming")
+		}
+	}
+
 	if flags.Lookup("tmp-path").Changed {
-		bfv2FileCacheConfigOptions.TmpPath = bfConfCliOptions.tmpPath
+		// blobfuse kept the last of repeated tmp paths, the file cache of Blobfuse2 has a single path as well
+		tmpPath := bfConfCliOptions.tmpPaths[len(bfConfCliOptions.tmpPaths)-1]
+		bfv2FileCacheConfigOptions.TmpPath = tmpPath
+		if useFileCache {
+			migration.mapped("--tmp-path", "file_cache.path")
+			for _, p := range bfConfCliOptions.tmpPaths[:len(bfConfCliOptions.tmpPaths)-1] {
+				if p != tmpPath {
+					migration.manual("--tmp-path="+p, "file_cache.path", fmt.Sprintf("only one cache path is supported, %s is used", tmpPath))
+				}
+			}
+		}
 	}
 	if flags.Lookup("cache-size-mb").Changed {
 		bfv2FileCacheConfigOptions.MaxSizeMB = bfConfCliOptions.cacheSize
@@ -464,45 +598,51 @@ func convertBfCliParameters(flags *pflag.FlagSet) error {
 	if flags.Lookup("empty-dir-check").Changed {
 		bfv2FileCacheConfigOptions.AllowNonEmpty = !bfConfCliOptions.emptyDirCheck
 	}
-	if flags.Lookup("use-adls").Changed {
+	if useFileCache {
+		for _, o := range fileCacheOptions[1:] {
+			cliOption(o.name, o.target)
+		}
+	}
+
+	if cliOption("use-adls", "azstorage.type") {
 		if bfConfCliOptions.useAdls {
 			bfv2StorageConfigOptions.AccountType = "adls"
 		} else {
 			bfv2StorageConfigOptions.AccountType = "block"
 		}
 	}
-	if flags.Lookup("use-https").Changed {
+	if cliOption("use-https", "azstorage.use-http") {
 		bfv2StorageConfigOptions.UseHTTP = !bfConfCliOptions.useHttps
 	}
-	if flags.Lookup("container-name").Changed {
+	if cliOption("container-name", "azstorage.container") {
 		bfv2StorageConfigOptions.Container = bfConfCliOptions.containerName
 	}
-	if flags.Lookup("max-concurrency").Changed {
+	if cliOption("max-concurrency", "azstorage.max-concurrency") {
 		bfv2StorageConfigOptions.MaxConcurrency = bfConfCliOptions.maxConcurrency
 	}
-	if flags.Lookup("cancel-list-on-mount-seconds").Changed {
+	if cliOption("cancel-list-on-mount-seconds", "azstorage.block-list-on-mount-sec") {
 		bfv2StorageConfigOptions.CancelListForSeconds = bfConfCliOptions.cancelListOnMount
 	}
-	if flags.Lookup("max-retry").Changed {
+	if cliOption("max-retry", "azstorage.max-retries") {
 		bfv2StorageConfigOptions.MaxRetries = bfConfCliOptions.maxRetry
 	}
-	if flags.Lookup("max-retry-interval-in-seconds").Changed {
+	if cliOption("max-retry-interval-in-seconds", "azstorage.max-retry-timeout-sec") {
 		bfv2StorageConfigOptions.MaxTimeout = bfConfCliOptions.maxRetryInterval
 	}
-	if flags.Lookup("retry-delay-factor").Changed {
+	if cliOption("retry-delay-factor", "azstorage.retry-backoff-sec") {
 		bfv2StorageConfigOptions.BackoffTime = bfConfCliOptions.retryDelayFactor
 	}
-	if flags.Lookup("http-proxy").Changed {
+	if cliOption("http-proxy", "azstorage.http-proxy") {
 		bfv2StorageConfigOptions.HttpProxyAddress = bfConfCliOptions.httpProxy
 	}
-	if flags.Lookup("https-proxy").Changed {
+	if cliOption("https-proxy", "azstorage.https-proxy") {
 		bfv2StorageConfigOptions.HttpsProxyAddress = bfConfCliOptions.httpsProxy
 	}
-	if flags.Lookup("d").Changed {
+	if cliOption("d", "libfuse.fuse-trace") {
 		bfv2FuseConfigOptions.EnableFuseTrace = bfConfCliOptions.fuseLogging
 		bfv2ForegroundOption = bfConfCliOptions.fuseLogging
 	}
-	if flags.Lookup("ignore-open-flags").Changed {
+	if cliOption("ignore-open-flags", "libfuse.ignore-open-flags") {
 		bfv2FuseConfigOptions.IgnoreOpenFlags = bfConfCliOptions.ignoreOpenFlags
 	}
 	return nil
@@ -512,7 +652,7 @@ func init() {
 	rootCmd.AddCommand(generateConfigCmd)
 	generateConfigCmd.Flags().StringVar(&outputFilePath, "output-file", "config.yaml", "Output Blobfuse configuration file.")
 
-	generateConfigCmd.Flags().StringVar(&bfConfCliOptions.tmpPath, "tmp-path", "", "Tmp location for the file cache.")
+	generateConfigCmd.Flags().StringArrayVar(&bfConfCliOptions.tmpPaths, "tmp-path", []string{}, "Tmp location for the file cache.")
 	generateConfigCmd.Flags().StringVar(&bfConfCliOptions.configFile, "config-file", "", "Input Blobfuse configuration file.")
 	generateConfigCmd.Flags().BoolVar(&bfConfCliOptions.useHttps, "use-https", false, "Enables HTTPS communication with Blob storage.")
 	generateConfigCmd.Flags().Uint32Var(&bfConfCliOptions.fileCacheTimeout, "file-cache-timeout-in-seconds", 0, "During this time, blobfuse will not check whether the file is up to date or not.")
@@ -546,6 +686,11 @@ func init() {
 	generateConfigCmd.Flags().StringSliceVarP(&libfuseOptions, "o", "o", []string{}, "FUSE options.")
 	generateConfigCmd.Flags().BoolVarP(&bfConfCliOptions.fuseLogging, "d", "d", false, "Mount with foreground and FUSE logs on.")
 	generateConfigCmd.Flags().BoolVar(&convertConfigOnly, "convert-config-only", false, "Don't mount - only convert v1 configuration to v2.")
+	generateConfigCmd.Flags().StringVar(&convertFormat, "output-format", convertFormatYAML, "Write the converted configuration as a yaml config file or as the flags of a mount command. Allowed values are yaml|flags.")
+	_ = generateConfigCmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{convertFormatYAML, convertFormatFlags}, cobra.ShellCompDirectiveNoFileComp
+	})
+	generateConfigCmd.Flags().BoolVar(&printMigrationReport, "report", false, "Print which options were mapped, dropped or need manual attention.")
 	generateConfigCmd.Flags().BoolVar(&bfConfCliOptions.ignoreOpenFlags, "ignore-open-flags", false, "Flag to ignore open flags unsupported by blobfuse.")
 
 	// options that are not available in V2:
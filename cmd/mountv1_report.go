@@ -0,0 +1,286 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/component/azstorage"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Forms the converted configuration can be written in
+const (
+	convertFormatYAML  = "yaml"
+	convertFormatFlags = "flags"
+)
+
+// migrationEntry : A blobfuse option and what the conversion did with it, Target is the Blobfuse2 setting it became
+type migrationEntry struct {
+	Option string
+	Target string
+	Note   string
+}
+
+// migrationReport : Options of a blobfuse mount split by how they carried over to Blobfuse2
+type migrationReport struct {
+	Mapped  []migrationEntry
+	Dropped []migrationEntry
+	Manual  []migrationEntry
+}
+
+var migration migrationReport
+
+func (r *migrationReport) mapped(option string, target string) {
+	r.Mapped = append(r.Mapped, migrationEntry{Option: option, Target: target})
+}
+
+func (r *migrationReport) mappedWithNote(option string, target string, note string) {
+	r.Mapped = append(r.Mapped, migrationEntry{Option: option, Target: target, Note: note})
+}
+
+func (r *migrationReport) dropped(option string, note string) {
+	r.Dropped = append(r.Dropped, migrationEntry{Option: option, Note: note})
+}
+
+func (r *migrationReport) manual(option string, target string, note string) {
+	r.Manual = append(r.Manual, migrationEntry{Option: option, Target: target, Note: note})
+}
+
+// print : One line per option under a heading for each outcome
+func (r *migrationReport) print(w io.Writer) {
+	groups := []struct {
+		title   string
+		entries []migrationEntry
+	}{
+		{"mapped", r.Mapped},
+		{"dropped", r.Dropped},
+		{"needs manual attention", r.Manual},
+	}
+
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s (%d):\n", g.title, len(g.entries))
+		for _, e := range g.entries {
+			line := "  " + e.Option
+			if e.Target != "" {
+				line += " -> " + e.Target
+			}
+			if e.Note != "" {
+				line += " : " + e.Note
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// v1AuthEnv : Environment variables blobfuse read its account and credentials from, with the Blobfuse2 key each one
+// still sets and the auth mode it is used with. MSI_ENDPOINT and MSI_SECRET are read by the identity library directly.
+var v1AuthEnv = []struct {
+	name   string
+	key    string
+	mode   string
+	secret bool
+}{
+	{azstorage.EnvAzStorageAccount, "azstorage.account-name", "", false},
+	{azstorage.EnvAzStorageAccountType, "azstorage.type", "", false},
+	{azstorage.EnvAzStorageBlobEndpoint, "azstorage.endpoint", "", false},
+	{azstorage.EnvAzStorageAuthType, "azstorage.mode", "", false},
+	{azstorage.EnvAzStorageAccessKey, "azstorage.account-key", "key", true},
+	{azstorage.EnvAzStorageSasToken, "azstorage.sas", "sas", true},
+	{azstorage.EnvAzStorageIdentityClientId, "azstorage.appid", "msi", false},
+	{azstorage.EnvAzStorageIdentityObjectId, "azstorage.objid", "msi", false},
+	{azstorage.EnvAzStorageIdentityResourceId, "azstorage.resid", "msi", false},
+	{"MSI_ENDPOINT", "", "msi", false},
+	{"MSI_SECRET", "", "msi", false},
+	{azstorage.EnvAzStorageSpnTenantId, "azstorage.tenantid", "spn", false},
+	{azstorage.EnvAzStorageSpnClientId, "azstorage.clientid", "spn", false},
+	{azstorage.EnvAzStorageSpnClientSecret, "azstorage.clientsecret", "spn", true},
+	{azstorage.EnvAzStorageAadEndpoint, "azstorage.aadendpoint", "spn", false},
+}
+
+// reportAuthEnv : Account and credential variables still exported take precedence over the generated config file while
+// the flags of a mount command take precedence over them. Credentials of another auth mode are no longer used at all.
+func reportAuthEnv(settings map[string]string, format string) {
+	mode, ok := os.LookupEnv(azstorage.EnvAzStorageAuthType)
+	if !ok {
+		mode = settings["azstorage.mode"]
+	}
+	mode = strings.ToLower(mode)
+
+	for _, e := range v1AuthEnv {
+		value, ok := os.LookupEnv(e.name)
+		if !ok {
+			continue
+		}
+
+		converted, set := settings[e.key]
+		switch {
+		case mode != "" && e.mode != "" && e.mode != mode:
+			migration.dropped(e.name, fmt.Sprintf("not used with auth mode %s, unset it before mounting", mode))
+		case e.key == "":
+			migration.mappedWithNote(e.name, "", "read by the identity library from the environment")
+		case !set:
+			migration.mappedWithNote(e.name, e.key, "still read from the environment")
+		case converted == value:
+			migration.mappedWithNote(e.name, e.key, "same value as the converted config")
+		case format == convertFormatFlags:
+			migration.dropped(e.name, fmt.Sprintf("overridden by --%s", e.key))
+		default:
+			migration.manual(e.name, e.key, "exported value overrides the one in the generated config")
+		}
+	}
+}
+
+// convertedSettings : Generated config as a yaml tree and flattened to dot separated keys
+func convertedSettings(pConf *PipelineConfig) (map[string]interface{}, map[string]string, error) {
+	data, err := yaml.Marshal(pConf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree := make(map[string]interface{})
+	err = yaml.Unmarshal(data, &tree)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings := make(map[string]string)
+	flattenSettings("", tree, settings)
+	return tree, settings, nil
+}
+
+func flattenSettings(prefix string, tree map[string]interface{}, settings map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenSettings(key, v, settings)
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			settings[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			settings[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// validateConverted : Problems found in the generated config, blobfuse accepted values Blobfuse2 refuses or options
+// that can not be combined any more, are left to the user to fix
+func validateConverted(tree map[string]interface{}) {
+	problems := append(config.ValidateMap(tree), validatePipeline(tree)...)
+	for _, p := range problems {
+		migration.manual(p.Key, "", p.Message)
+	}
+}
+
+// convertedMountFlags : Mount flags of the generated keys that are not named after the key itself
+var convertedMountFlags = map[string]string{
+	"foreground":        "--foreground",
+	"read-only":         "--read-only",
+	"allow-other":       "-o allow_other",
+	"nonempty":          "-o nonempty",
+	"logging.type":      "--log-type",
+	"logging.level":     "--log-level",
+	"logging.file-path": "--log-file-path",
+	"components":        "--components",
+}
+
+// mountCommandLine : The generated config as a single mount command. Values left at their zero value are omitted and
+// credentials are not put on the command line, where any user can read them, but left to the environment.
+func mountCommandLine(path string, settings map[string]string) string {
+	if path == "" {
+		path = "<mount-path>"
+	}
+	args := []string{"blobfuse2", "mount", shellQuote(path)}
+
+	secrets := make(map[string]string)
+	for _, e := range v1AuthEnv {
+		if e.secret {
+			secrets[e.key] = e.name
+		}
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := settings[key]
+		if value == "" || value == "0" || value == "false" {
+			continue
+		}
+
+		if env, ok := secrets[key]; ok {
+			migration.manual(key, env, fmt.Sprintf("credentials are not put on the command line, export %s instead", env))
+			continue
+		}
+
+		flag, ok := convertedMountFlags[key]
+		switch {
+		case ok && value == "true":
+			args = append(args, flag)
+		case ok:
+			args = append(args, flag+"="+shellQuote(value))
+		case strings.Contains(key, "."):
+			args = append(args, "--"+key+"="+shellQuote(value))
+		default:
+			migration.manual(key, "", "no mount flag for it, set it in a config file")
+		}
+	}
+
+	return strings.Join(args, " ")
+}
+
+// shellQuote : Single quote a value the shell would otherwise split or expand
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:,=@%+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
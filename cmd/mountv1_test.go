@@ -64,6 +64,7 @@ type generateConfigTestSuite struct {
 func (suite *generateConfigTestSuite) SetupTest() {
 	suite.assert = assert.New(suite.T())
 	libfuseOptions = make([]string, 0)
+	bfConfCliOptions.tmpPaths = nil
 	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
 	if err != nil {
 		panic("Unable to set silent logger as default.")
@@ -73,6 +74,9 @@ func (suite *generateConfigTestSuite) SetupTest() {
 func (suite *generateConfigTestSuite) cleanupTest() {
 	resetCLIFlags(*generateConfigCmd)
 	resetGenOneOptions()
+	convertConfigOnly = false
+	convertFormat = convertFormatYAML
+	printMigrationReport = false
 	viper.Reset()
 }
 
@@ -937,3 +941,144 @@ func (suite *generateConfigTestSuite) TestInvalidAccountType() {
 	suite.assert.NotNil(err)
 	suite.assert.Contains(op, "invalid account type")
 }
+
+func (suite *generateConfigTestSuite) TestReport() {
+	defer suite.cleanupTest()
+	name := generateFileName()
+	v1ConfigFile, _ := os.CreateTemp("", name+".tmp.cfg")
+	defer os.Remove(v1ConfigFile.Name())
+	v1ConfigFile.WriteString("accountName myAccountName\ncontainerName myContainerName\nmsiEndpoint http://localhost:1234/token\n")
+	v2ConfigFile, _ := os.CreateTemp("", name+".tmp.yaml")
+	defer os.Remove(v2ConfigFile.Name())
+
+	op, err := executeCommandC(rootCmd, "mountv1", "--convert-config-only=true", "--report", fmt.Sprintf("--output-file=%s", v2ConfigFile.Name()),
+		fmt.Sprintf("--config-file=%s", v1ConfigFile.Name()), "--container-name=otherContainer", "--set-content-type=true", "--block-size-mb=16", "-o nodev")
+	suite.assert.Nil(err)
+
+	suite.assert.Contains(op, "mapped (")
+	suite.assert.Contains(op, "  accountName -> azstorage.account-name\n")
+	suite.assert.Contains(op, "  --container-name -> azstorage.container\n")
+	suite.assert.Contains(op, "  containerName : overridden by --container-name\n")
+	suite.assert.Contains(op, "  --set-content-type : not supported in Blobfuse2\n")
+	suite.assert.Contains(op, "  --block-size-mb : only used with --streaming\n")
+	suite.assert.Contains(op, "  -o nodev : ignored by Blobfuse2\n")
+	suite.assert.Contains(op, "  msiEndpoint -> MSI_ENDPOINT : not a config option in Blobfuse2, export MSI_ENDPOINT instead\n")
+}
+
+func (suite *generateConfigTestSuite) TestReportInvalidValue() {
+	defer suite.cleanupTest()
+	name := generateFileName()
+	v1ConfigFile, _ := os.CreateTemp("", name+".tmp.cfg")
+	defer os.Remove(v1ConfigFile.Name())
+	v1ConfigFile.WriteString("accountName myAccountName\nlogLevel LOG_VERBOSE\n")
+	v2ConfigFile, _ := os.CreateTemp("", name+".tmp.yaml")
+	defer os.Remove(v2ConfigFile.Name())
+
+	op, err := executeCommandC(rootCmd, "mountv1", "--convert-config-only=true", "--report", fmt.Sprintf("--output-file=%s", v2ConfigFile.Name()),
+		fmt.Sprintf("--config-file=%s", v1ConfigFile.Name()))
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "needs manual attention (1):\n  logging.level : LOG_VERBOSE is not supported")
+}
+
+func (suite *generateConfigTestSuite) TestManualAttentionHint() {
+	defer suite.cleanupTest()
+	name := generateFileName()
+	v1ConfigFile, _ := os.CreateTemp("", name+".tmp.cfg")
+	defer os.Remove(v1ConfigFile.Name())
+	v1ConfigFile.WriteString("accountName myAccountName\nlogLevel LOG_VERBOSE\n")
+	v2ConfigFile, _ := os.CreateTemp("", name+".tmp.yaml")
+	defer os.Remove(v2ConfigFile.Name())
+
+	// Without --report the user is told on stderr there is more to look at
+	op, err := executeCommandC(rootCmd, "mountv1", "--convert-config-only=true", fmt.Sprintf("--output-file=%s", v2ConfigFile.Name()),
+		fmt.Sprintf("--config-file=%s", v1ConfigFile.Name()))
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "1 blobfuse options need manual attention, rerun with --report to list them")
+}
+
+func (suite *generateConfigTestSuite) TestMultipleTmpPath() {
+	defer suite.cleanupTest()
+	name := generateFileName()
+	v1ConfigFile, _ := os.CreateTemp("", name+".tmp.cfg")
+	defer os.Remove(v1ConfigFile.Name())
+	v1ConfigFile.WriteString("accountName myAccountName")
+	v2ConfigFile, _ := os.CreateTemp("", name+".tmp.yaml")
+	defer os.Remove(v2ConfigFile.Name())
+
+	op, err := executeCommandC(rootCmd, "mountv1", "--convert-config-only=true", "--report", fmt.Sprintf("--output-file=%s", v2ConfigFile.Name()),
+		fmt.Sprintf("--config-file=%s", v1ConfigFile.Name()), "--tmp-path=/mnt/first", "--tmp-path=/mnt/second")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "  --tmp-path -> file_cache.path\n")
+	suite.assert.Contains(op, "  --tmp-path=/mnt/first -> file_cache.path : only one cache path is supported, /mnt/second is used\n")
+
+	options := file_cache.FileCacheOptions{}
+	viper.SetConfigType("yaml")
+	config.ReadFromConfigFile(v2ConfigFile.Name())
+	config.UnmarshalKey("file_cache", &options)
+	suite.assert.Equal("/mnt/second", options.TmpPath)
+}
+
+func (suite *generateConfigTestSuite) TestOutputFormatFlags() {
+	defer suite.cleanupTest()
+	name := generateFileName()
+	v1ConfigFile, _ := os.CreateTemp("", name+".tmp.cfg")
+	defer os.Remove(v1ConfigFile.Name())
+	v1ConfigFile.WriteString("accountName myAccountName\naccountKey myAccountKey\nauthType Key\ncontainerName myContainerName\n")
+
+	op, err := executeCommandC(rootCmd, "mountv1", "/mnt/my blob", "--convert-config-only=true", "--output-format=flags", "--report",
+		fmt.Sprintf("--config-file=%s", v1ConfigFile.Name()), "--use-attr-cache=true", "-o allow_other")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "blobfuse2 mount '/mnt/my blob' ")
+	suite.assert.Contains(op, " --azstorage.account-name=myAccountName ")
+	suite.assert.Contains(op, " --azstorage.container=myContainerName ")
+	suite.assert.Contains(op, " --azstorage.mode=key ")
+	suite.assert.Contains(op, " --components=libfuse,file_cache,attr_cache,azstorage ")
+	suite.assert.Contains(op, " -o allow_other ")
+	suite.assert.NotContains(op, "myAccountKey")
+	suite.assert.Contains(op, "  azstorage.account-key -> AZURE_STORAGE_ACCESS_KEY : credentials are not put on the command line, export AZURE_STORAGE_ACCESS_KEY instead\n")
+}
+
+func (suite *generateConfigTestSuite) TestOutputFormatFlagsNeedsConvertOnly() {
+	defer suite.cleanupTest()
+
+	op, err := executeCommandC(rootCmd, "mountv1", "/mnt/blob", "--output-format=flags", "--container-name=myContainerName")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitUsage, exitCode(err))
+	suite.assert.Contains(op, "needs --convert-config-only")
+}
+
+func (suite *generateConfigTestSuite) TestInvalidOutputFormat() {
+	defer suite.cleanupTest()
+
+	op, err := executeCommandC(rootCmd, "mountv1", "--convert-config-only=true", "--output-format=json")
+	suite.assert.NotNil(err)
+	suite.assert.Equal(exitUsage, exitCode(err))
+	suite.assert.Contains(op, "invalid output format json")
+}
+
+func (suite *generateConfigTestSuite) TestReportAuthEnv() {
+	defer suite.cleanupTest()
+	name := generateFileName()
+	v1ConfigFile, _ := os.CreateTemp("", name+".tmp.cfg")
+	defer os.Remove(v1ConfigFile.Name())
+	v1ConfigFile.WriteString("accountName myAccountName\naccountKey myAccountKey\nauthType Key\ncontainerName myContainerName\n")
+	v2ConfigFile, _ := os.CreateTemp("", name+".tmp.yaml")
+	defer os.Remove(v2ConfigFile.Name())
+
+	os.Setenv("AZURE_STORAGE_ACCOUNT", "myAccountName")
+	defer os.Unsetenv("AZURE_STORAGE_ACCOUNT")
+	os.Setenv("AZURE_STORAGE_ACCESS_KEY", "myOldAccountKey")
+	defer os.Unsetenv("AZURE_STORAGE_ACCESS_KEY")
+	os.Setenv("AZURE_STORAGE_SAS_TOKEN", "mySasToken")
+	defer os.Unsetenv("AZURE_STORAGE_SAS_TOKEN")
+	os.Setenv("AZURE_STORAGE_ACCOUNT_TYPE", "block")
+	defer os.Unsetenv("AZURE_STORAGE_ACCOUNT_TYPE")
+
+	op, err := executeCommandC(rootCmd, "mountv1", "--convert-config-only=true", "--report", fmt.Sprintf("--output-file=%s", v2ConfigFile.Name()),
+		fmt.Sprintf("--config-file=%s", v1ConfigFile.Name()))
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "  AZURE_STORAGE_ACCOUNT -> azstorage.account-name : same value as the converted config\n")
+	suite.assert.Contains(op, "  AZURE_STORAGE_ACCOUNT_TYPE -> azstorage.type : still read from the environment\n")
+	suite.assert.Contains(op, "  AZURE_STORAGE_ACCESS_KEY -> azstorage.account-key : exported value overrides the one in the generated config\n")
+	suite.assert.Contains(op, "  AZURE_STORAGE_SAS_TOKEN : not used with auth mode key, unset it before mounting\n")
+}
@@ -0,0 +1,126 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+)
+
+// Workload presets : component defaults tuned for a kind of workload.
+// Values are only defaults, anything given in the config file, environment or on the command line wins over them.
+var presets = map[string]map[string]interface{}{
+	// Mixed reads and writes of small and medium files, the defaults of the components spelled out
+	"general": {
+		"libfuse.attribute-expiration-sec":      120,
+		"libfuse.entry-expiration-sec":          120,
+		"libfuse.negative-entry-expiration-sec": 120,
+		"file_cache.policy":                     "lru",
+		"file_cache.timeout-sec":                120,
+		"attr_cache.timeout-sec":                120,
+		"azstorage.max-concurrency":             32,
+	},
+
+	// Large files read and written in parallel by many processes, files are fetched and uploaded in large blocks
+	"hpc": {
+		"libfuse.attribute-expiration-sec":       300,
+		"libfuse.entry-expiration-sec":           300,
+		"libfuse.negative-entry-expiration-sec":  300,
+		"file_cache.timeout-sec":                 300,
+		"file_cache.pipelined-upload":            true,
+		"file_cache.pipelined-block-size-mb":     32,
+		"file_cache.partial-cache-threshold-mb":  1024,
+		"file_cache.partial-cache-block-size-mb": 32,
+		"stream.block-size-mb":                   32,
+		"attr_cache.timeout-sec":                 300,
+		"azstorage.block-size-mb":                32,
+		"azstorage.max-concurrency":              64,
+	},
+
+	// Dataset read over and over, once per epoch, and seldom changed, so it is kept cached for long
+	"ml-training": {
+		"libfuse.attribute-expiration-sec":      7200,
+		"libfuse.entry-expiration-sec":          7200,
+		"libfuse.negative-entry-expiration-sec": 7200,
+		"libfuse.kernel-cache":                  true,
+		"file_cache.policy":                     "lru",
+		"file_cache.timeout-sec":                7200,
+		"attr_cache.timeout-sec":                7200,
+		"azstorage.block-size-mb":               8,
+		"azstorage.max-concurrency":             64,
+	},
+
+	// Files written once, in one go, and hardly read back, so they leave the cache as soon as they are uploaded
+	"backup": {
+		"libfuse.attribute-expiration-sec":      30,
+		"libfuse.entry-expiration-sec":          30,
+		"libfuse.negative-entry-expiration-sec": 30,
+		"file_cache.timeout-sec":                0,
+		"file_cache.pipelined-upload":           true,
+		"file_cache.pipelined-block-size-mb":    64,
+		"attr_cache.timeout-sec":                30,
+		"azstorage.block-size-mb":               64,
+		"azstorage.max-concurrency":             32,
+	},
+}
+
+// presetNames : Names of the presets, in order
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyPreset : Set the values of the preset as defaults of the config
+func applyPreset(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	values, ok := presets[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("invalid preset %s, allowed values are %s", name, strings.Join(presetNames(), "|"))
+	}
+
+	for key, value := range values {
+		config.SetDefault(key, value)
+	}
+	return nil
+}
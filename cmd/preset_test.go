@@ -0,0 +1,151 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type presetTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *presetTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	options = mountOptions{}
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *presetTestSuite) cleanupTest() {
+	resetCLIFlags(*mountCmd)
+	viper.Reset()
+}
+
+func TestPreset(t *testing.T) {
+	suite.Run(t, new(presetTestSuite))
+}
+
+func (suite *presetTestSuite) TestPresetNames() {
+	suite.assert.Equal([]string{"backup", "general", "hpc", "ml-training"}, presetNames())
+}
+
+func (suite *presetTestSuite) TestApplyNoPreset() {
+	defer suite.cleanupTest()
+
+	suite.assert.Nil(applyPreset(""))
+	suite.assert.False(config.IsSet("file_cache.timeout-sec"))
+}
+
+func (suite *presetTestSuite) TestApplyInvalidPreset() {
+	defer suite.cleanupTest()
+
+	err := applyPreset("archive")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "invalid preset archive")
+	suite.assert.Contains(err.Error(), "backup|general|hpc|ml-training")
+}
+
+func (suite *presetTestSuite) TestApplyPreset() {
+	defer suite.cleanupTest()
+
+	suite.assert.Nil(applyPreset("ML-Training"))
+	suite.assert.True(config.IsSet("libfuse.entry-expiration-sec"))
+
+	timeout := uint32(0)
+	suite.assert.Nil(config.UnmarshalKey("file_cache.timeout-sec", &timeout))
+	suite.assert.EqualValues(7200, timeout)
+
+	kernelCache := false
+	suite.assert.Nil(config.UnmarshalKey("libfuse.kernel-cache", &kernelCache))
+	suite.assert.True(kernelCache)
+}
+
+func (suite *presetTestSuite) TestConfigOverridesPreset() {
+	defer suite.cleanupTest()
+
+	viper.SetConfigType("yaml")
+	err := config.ReadFromConfigBuffer([]byte("file_cache:\n  path: /tmp/cache\n  timeout-sec: 15\nazstorage:\n  max-concurrency: 8\n"))
+	suite.assert.Nil(err)
+	suite.assert.Nil(applyPreset("hpc"))
+
+	fileCache := struct {
+		Path            string `config:"path"`
+		Timeout         uint32 `config:"timeout-sec"`
+		PipelinedUpload bool   `config:"pipelined-upload"`
+		BlockSizeMB     uint32 `config:"pipelined-block-size-mb"`
+	}{}
+	suite.assert.Nil(config.UnmarshalKey("file_cache", &fileCache))
+	suite.assert.Equal("/tmp/cache", fileCache.Path)
+	suite.assert.EqualValues(15, fileCache.Timeout)
+	suite.assert.True(fileCache.PipelinedUpload)
+	suite.assert.EqualValues(32, fileCache.BlockSizeMB)
+
+	storage := struct {
+		BlockSize      int64  `config:"block-size-mb"`
+		MaxConcurrency uint16 `config:"max-concurrency"`
+	}{}
+	suite.assert.Nil(config.UnmarshalKey("azstorage", &storage))
+	suite.assert.EqualValues(32, storage.BlockSize)
+	suite.assert.EqualValues(8, storage.MaxConcurrency)
+}
+
+func (suite *presetTestSuite) TestMountInvalidPreset() {
+	defer suite.cleanupTest()
+
+	mntDir, err := os.MkdirTemp("", "mntdir")
+	suite.assert.Nil(err)
+	defer os.RemoveAll(mntDir)
+
+	confFile := filepath.Join(suite.T().TempDir(), "config.yaml")
+	suite.assert.Nil(os.WriteFile(confFile, []byte(configMountTest), 0644))
+
+	op, err := executeCommandC(rootCmd, "mount", mntDir, fmt.Sprintf("--config-file=%s", confFile), "--preset=archive")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid preset archive")
+}
@@ -0,0 +1,73 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+var reloadCmd = &cobra.Command{
+	Use:               "reload <mount path>",
+	Short:             "Apply changes of the config file to a running mount",
+	Long:              "Read the config file of a running mount again and apply the settings which can change while mounted: logging, cache size limits and timeouts, background upload bandwidth, stream buffers and storage block size and concurrency. Other settings take a remount. Sending SIGHUP to the mount process does the same.",
+	SuggestFor:        []string{"relaod", "reconfigure"},
+	Example:           "blobfuse2 reload ~/mount_path",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeMountPaths,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mountPath, _, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := admin.Send(mountPath, admin.Request{Verb: admin.VerbReload})
+		if err != nil {
+			return fmt.Errorf("failed to reload config of %s [%s]", mountPath, err.Error())
+		}
+
+		if resp.Message != "" {
+			fmt.Println(resp.Message)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reloadCmd)
+}
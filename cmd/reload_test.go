@@ -0,0 +1,112 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type reloadCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *reloadCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *reloadCmdTestSuite) cleanupTest() {
+	listMountPoints = common.ListMountPoints
+}
+
+func TestReloadCommand(t *testing.T) {
+	suite.Run(t, new(reloadCmdTestSuite))
+}
+
+func (suite *reloadCmdTestSuite) TestReloadNotMounted() {
+	defer suite.cleanupTest()
+	listMountPoints = func() ([]string, error) { return []string{}, nil }
+
+	op, err := executeCommandC(rootCmd, "reload", suite.T().TempDir())
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "is not inside a blobfuse2 mount")
+}
+
+func (suite *reloadCmdTestSuite) TestReload() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	configFile := filepath.Join(suite.T().TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte("file_cache:\n  max-size-mb: 100\n"), 0644)
+	suite.assert.Nil(err)
+	err = config.ReadFromConfigFile(configFile)
+	suite.assert.Nil(err)
+
+	admin.RegisterHandler(admin.VerbReload, "mount", reloadConfig)
+	defer admin.UnregisterHandler(admin.VerbReload, "mount")
+
+	err = admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	err = os.WriteFile(configFile, []byte("file_cache:\n  max-size-mb: 200\n"), 0644)
+	suite.assert.Nil(err)
+
+	_, err = executeCommandC(rootCmd, "reload", mountPath)
+	suite.assert.Nil(err)
+
+	maxSize := 0
+	err = config.UnmarshalKey("file_cache.max-size-mb", &maxSize)
+	suite.assert.Nil(err)
+	suite.assert.Equal(200, maxSize)
+}
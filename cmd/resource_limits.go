@@ -0,0 +1,134 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/membudget"
+)
+
+// resourceLimitsOptions : Ceilings on the memory and CPU the mount may use
+type resourceLimitsOptions struct {
+	MemoryMB   uint64 `config:"memory-mb" yaml:"memory-mb,omitempty"`
+	CPUPercent uint32 `config:"cpu-percent" yaml:"cpu-percent,omitempty"`
+	Cgroup     string `config:"cgroup" yaml:"cgroup,omitempty"`
+}
+
+const minResourceMemoryMB = 64
+
+// Buffers of the components may hold this share of the memory ceiling, the rest is left to the runtime and metadata
+const bufferShareOfMemory = 0.75
+
+// Go runtime collects garbage eagerly once the heap reaches this share of the memory ceiling
+const runtimeShareOfMemory = 0.9
+
+func (opt resourceLimitsOptions) validate() error {
+	if opt.MemoryMB != 0 && opt.MemoryMB < minResourceMemoryMB {
+		return fmt.Errorf("resource-limits.memory-mb shall be at least %d", minResourceMemoryMB)
+	}
+	if opt.Cgroup != "" {
+		if _, err := cgroupPath(opt.Cgroup); err != nil {
+			return fmt.Errorf("invalid resource-limits.cgroup [%s]", err.Error())
+		}
+	}
+	return nil
+}
+
+// placeInCgroup : Move the process into the cgroup of the mount and set its ceilings there, the given cgroup or else a
+// child of the current one. Returns the cgroup, empty if the process stays where it is as none was given and none
+// could be joined.
+func placeInCgroup(opt resourceLimitsOptions, mountPath string) (string, error) {
+	if opt.MemoryMB == 0 && opt.CPUPercent == 0 && opt.Cgroup == "" {
+		return "", nil
+	}
+
+	memory := int64(opt.MemoryMB) * common.MbToBytes
+	if opt.Cgroup != "" {
+		path, err := cgroupPath(opt.Cgroup)
+		if err == nil {
+			err = joinCgroup(path, memory, opt.CPUPercent)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to place mount in cgroup %s [%s]", opt.Cgroup, err.Error())
+		}
+		return path, nil
+	}
+
+	path, err := mountCgroup(mountPath)
+	if err == nil {
+		err = joinCgroup(path, memory, opt.CPUPercent)
+	}
+	if err != nil {
+		log.Warn("Mount::placeInCgroup : mount not placed in a cgroup [%s]", err.Error())
+		return "", nil
+	}
+	return path, nil
+}
+
+// applyResourceLimits : Hold the mount to its ceilings through a cgroup, see placeInCgroup. Where no cgroup can be
+// joined the runtime and the memory budget of the buffers keep memory down and GOMAXPROCS the CPU.
+func applyResourceLimits(opt resourceLimitsOptions, mountPath string) error {
+	if opt.MemoryMB == 0 && opt.CPUPercent == 0 && opt.Cgroup == "" {
+		return nil
+	}
+
+	memory := int64(opt.MemoryMB) * common.MbToBytes
+	if memory > 0 {
+		membudget.SetLimit(int64(float64(memory) * bufferShareOfMemory))
+		debug.SetMemoryLimit(int64(float64(memory) * runtimeShareOfMemory))
+	}
+
+	path, err := placeInCgroup(opt, mountPath)
+	if err != nil {
+		return err
+	}
+
+	if path == "" && opt.CPUPercent > 0 {
+		// Only threads running Go code are limited, which are kept at 2 or more like setGOConfig does
+		procs := int((opt.CPUPercent + 99) / 100)
+		if procs < 2 {
+			procs = 2
+		}
+		runtime.GOMAXPROCS(procs)
+	}
+
+	log.Info("Mount::applyResourceLimits : cgroup [%s], memory %d MB, cpu %d%%, buffers may hold %d bytes",
+		path, opt.MemoryMB, opt.CPUPercent, membudget.Limit())
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Mount point of the cgroup v2 hierarchy, a variable so tests can point it elsewhere
+var cgroupRoot = "/sys/fs/cgroup"
+
+const cgroupCPUPeriod = 100000
+
+// Cgroup of the process, a variable so tests can point it elsewhere
+var procSelfCgroup = "/proc/self/cgroup"
+
+// cgroupPath : Cgroups are named relative to the root of the hierarchy, with or without it as prefix. Names leading out
+// of the hierarchy are refused, as limits are written to the files of the cgroup.
+func cgroupPath(name string) (string, error) {
+	path := filepath.Clean(name)
+	if path != cgroupRoot && !strings.HasPrefix(path, cgroupRoot+"/") {
+		path = filepath.Join(cgroupRoot, path)
+	}
+
+	rel, err := filepath.Rel(cgroupRoot, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("cgroup %s is not within %s", name, cgroupRoot)
+	}
+	return path, nil
+}
+
+// joinCgroup : Create the cgroup if it does not exist, move the process into it and set its ceilings. The process is
+// moved first, as controllers are only enabled for the cgroup once its parent, the cgroup the process may come from,
+// holds no process.
+func joinCgroup(path string, memory int64, cpuPercent uint32) error {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return errors.New("cgroup v2 hierarchy not found at " + cgroupRoot)
+	}
+
+	err := os.MkdirAll(path, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(path, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0644)
+	if err != nil {
+		return err
+	}
+
+	controllers := []string{}
+	if memory > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if cpuPercent > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	err = enableControllers(filepath.Dir(path), controllers)
+	if err != nil {
+		return fmt.Errorf("failed to enable %s for %s [%s]", strings.Join(controllers, ", "), path, err.Error())
+	}
+
+	if memory > 0 {
+		err = os.WriteFile(filepath.Join(path, "memory.max"), []byte(strconv.FormatInt(memory, 10)), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cpuPercent > 0 {
+		quota := int64(cpuPercent) * cgroupCPUPeriod / 100
+		err = os.WriteFile(filepath.Join(path, "cpu.max"), []byte(fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enableControllers : Enable the controllers for the children of a cgroup, unless they are already
+func enableControllers(parent string, controllers []string) error {
+	data, _ := os.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+	enabled := make(map[string]bool)
+	for _, controller := range strings.Fields(string(data)) {
+		enabled[controller] = true
+	}
+
+	missing := []string{}
+	for _, controller := range controllers {
+		if !enabled[controller] {
+			missing = append(missing, "+"+controller)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(strings.Join(missing, " ")), 0644)
+}
+
+// mountCgroup : Cgroup of the mount, a child of the one the process runs in named after the mount path, so the mount
+// stays within the unit or scope it was started in. A process already in the cgroup of the mount, e.g. a mount
+// started in background or by a supervisor, stays in it.
+func mountCgroup(mountPath string) (string, error) {
+	data, err := os.ReadFile(procSelfCgroup)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if current, found := strings.CutPrefix(line, "0::"); found {
+			name := fmt.Sprintf("blobfuse2-%08x", crc32.ChecksumIEEE([]byte(mountPath)))
+			if filepath.Base(current) == name {
+				return filepath.Join(cgroupRoot, current), nil
+			}
+			return filepath.Join(cgroupRoot, current, name), nil
+		}
+	}
+	return "", errors.New("process is not in a cgroup v2 hierarchy")
+}
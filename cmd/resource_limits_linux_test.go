@@ -0,0 +1,205 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/membudget"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type resourceLimitsTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	root   string
+}
+
+func (suite *resourceLimitsTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+
+	suite.root = cgroupRoot
+	cgroupRoot = suite.T().TempDir()
+	suite.assert.Nil(os.WriteFile(filepath.Join(cgroupRoot, "cgroup.controllers"), []byte("cpu memory"), 0644))
+}
+
+func (suite *resourceLimitsTestSuite) cleanupTest() {
+	cgroupRoot = suite.root
+	membudget.SetLimit(0)
+	debug.SetMemoryLimit(math.MaxInt64)
+}
+
+func TestResourceLimits(t *testing.T) {
+	suite.Run(t, new(resourceLimitsTestSuite))
+}
+
+func (suite *resourceLimitsTestSuite) TestValidate() {
+	defer suite.cleanupTest()
+
+	suite.assert.Nil(resourceLimitsOptions{}.validate())
+	suite.assert.Nil(resourceLimitsOptions{MemoryMB: 512}.validate())
+
+	err := resourceLimitsOptions{MemoryMB: 16}.validate()
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "at least 64")
+
+	err = resourceLimitsOptions{MemoryMB: 512, Cgroup: "../../etc"}.validate()
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "invalid resource-limits.cgroup")
+}
+
+func (suite *resourceLimitsTestSuite) TestCgroupPath() {
+	defer suite.cleanupTest()
+
+	want := filepath.Join(cgroupRoot, "blobfuse2.slice/data")
+	for _, name := range []string{"blobfuse2.slice/data", "/blobfuse2.slice/data", want, "/../blobfuse2.slice/data"} {
+		path, err := cgroupPath(name)
+		suite.assert.Nil(err, name)
+		suite.assert.Equal(want, path, name)
+	}
+
+	// Limits are written to the cgroup as root, so it has to stay within the hierarchy
+	for _, name := range []string{"..", "../../etc", "blobfuse2/../../etc", "/", "."} {
+		_, err := cgroupPath(name)
+		suite.assert.NotNil(err, name)
+	}
+}
+
+func (suite *resourceLimitsTestSuite) TestMountCgroup() {
+	defer suite.cleanupTest()
+	defer func(path string) { procSelfCgroup = path }(procSelfCgroup)
+
+	procSelfCgroup = filepath.Join(suite.T().TempDir(), "cgroup")
+	suite.assert.Nil(os.WriteFile(procSelfCgroup, []byte("0::/system.slice/blobfuse2.service\n"), 0644))
+
+	// Mount stays within the unit it was started in
+	path, err := mountCgroup("/mnt/data")
+	suite.assert.Nil(err)
+	suite.assert.Equal(filepath.Join(cgroupRoot, "system.slice/blobfuse2.service"), filepath.Dir(path))
+	suite.assert.True(strings.HasPrefix(filepath.Base(path), "blobfuse2-"))
+
+	again, _ := mountCgroup("/mnt/data")
+	other, _ := mountCgroup("/mnt/other")
+	suite.assert.Equal(path, again)
+	suite.assert.NotEqual(path, other)
+
+	// A process started in the cgroup of the mount stays in it
+	current := strings.TrimPrefix(path, cgroupRoot)
+	suite.assert.Nil(os.WriteFile(procSelfCgroup, []byte("0::"+current+"\n"), 0644))
+	again, err = mountCgroup("/mnt/data")
+	suite.assert.Nil(err)
+	suite.assert.Equal(path, again)
+
+	suite.assert.Nil(os.WriteFile(procSelfCgroup, []byte("1:name=systemd:/\n"), 0644))
+	_, err = mountCgroup("/mnt/data")
+	suite.assert.NotNil(err)
+}
+
+func (suite *resourceLimitsTestSuite) TestNoLimits() {
+	defer suite.cleanupTest()
+
+	suite.assert.Nil(applyResourceLimits(resourceLimitsOptions{}, "/mnt/data"))
+	suite.assert.EqualValues(0, membudget.Limit())
+}
+
+func (suite *resourceLimitsTestSuite) TestJoinCgroup() {
+	defer suite.cleanupTest()
+
+	err := applyResourceLimits(resourceLimitsOptions{MemoryMB: 1024, CPUPercent: 150, Cgroup: "blobfuse2/data"}, "/mnt/data")
+	suite.assert.Nil(err)
+	suite.assert.EqualValues(768*common.MbToBytes, membudget.Limit())
+
+	dir := filepath.Join(cgroupRoot, "blobfuse2/data")
+	memory, _ := os.ReadFile(filepath.Join(dir, "memory.max"))
+	suite.assert.Equal(strconv.Itoa(1024*common.MbToBytes), string(memory))
+
+	cpu, _ := os.ReadFile(filepath.Join(dir, "cpu.max"))
+	suite.assert.Equal("150000 100000", string(cpu))
+
+	procs, _ := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	suite.assert.Equal(strconv.Itoa(os.Getpid()), string(procs))
+
+	controllers, _ := os.ReadFile(filepath.Join(cgroupRoot, "blobfuse2", "cgroup.subtree_control"))
+	suite.assert.Equal("+memory +cpu", string(controllers))
+
+	// Controllers enabled already are left alone
+	suite.assert.Nil(os.WriteFile(filepath.Join(cgroupRoot, "blobfuse2", "cgroup.subtree_control"), []byte("cpu memory"), 0644))
+	suite.assert.Nil(joinCgroup(dir, 1024*common.MbToBytes, 150))
+	controllers, _ = os.ReadFile(filepath.Join(cgroupRoot, "blobfuse2", "cgroup.subtree_control"))
+	suite.assert.Equal("cpu memory", string(controllers))
+}
+
+func (suite *resourceLimitsTestSuite) TestJoinCgroupOutsideHierarchy() {
+	defer suite.cleanupTest()
+
+	err := applyResourceLimits(resourceLimitsOptions{MemoryMB: 1024, Cgroup: "../escaped"}, "/mnt/data")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "not within")
+
+	_, err = os.Stat(filepath.Join(filepath.Dir(cgroupRoot), "escaped"))
+	suite.assert.True(os.IsNotExist(err))
+}
+
+func (suite *resourceLimitsTestSuite) TestJoinCgroupWithoutHierarchy() {
+	defer suite.cleanupTest()
+	_ = os.Remove(filepath.Join(cgroupRoot, "cgroup.controllers"))
+
+	err := applyResourceLimits(resourceLimitsOptions{MemoryMB: 1024, Cgroup: "blobfuse2/data"}, "/mnt/data")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "failed to place mount in cgroup blobfuse2/data")
+}
+
+func (suite *resourceLimitsTestSuite) TestFallbackWithoutCgroup() {
+	defer suite.cleanupTest()
+	_ = os.Remove(filepath.Join(cgroupRoot, "cgroup.controllers"))
+
+	// Without a cgroup to join the memory budget still holds buffers down
+	err := applyResourceLimits(resourceLimitsOptions{MemoryMB: 256}, "/mnt/data")
+	suite.assert.Nil(err)
+	suite.assert.EqualValues(192*common.MbToBytes, membudget.Limit())
+}
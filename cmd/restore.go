@@ -0,0 +1,177 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/component/azstorage"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/cobra"
+)
+
+type restoreOptions struct {
+	ConfigFile string
+	List       bool
+	Trash      bool
+}
+
+var restoreOpts restoreOptions
+
+var restoreCmd = &cobra.Command{
+	Use:               "restore [path]",
+	Short:             "List or restore soft-deleted files and directories",
+	Long:              "List or restore soft-deleted files and directories. Soft-delete shall be enabled on the storage account for deleted paths to be retained.\nWith --trash, files moved to the trash of the container on delete are listed or restored instead.",
+	SuggestFor:        []string{"undelete", "rstore"},
+	Example:           "blobfuse2 restore dir/file.txt --config-file=config.yaml\nblobfuse2 restore --list --config-file=config.yaml\nblobfuse2 restore dir/file.txt --trash --config-file=config.yaml",
+	Args:              cobra.MaximumNArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ""
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		if !restoreOpts.List && path == "" {
+			return errors.New("path to be restored not provided, check usage")
+		}
+
+		azComponent, err := getAzStorageComponent(restoreOpts.ConfigFile)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = azComponent.Stop() }()
+
+		return restoreStorage(cmd.OutOrStdout(), azComponent, path)
+	},
+}
+
+// storageRestorer : Part of the azstorage component restore needs, so tests can stand in for storage
+type storageRestorer interface {
+	ListDeleted(prefix string) ([]*internal.ObjAttr, error)
+	ListTrash(prefix string) ([]*internal.ObjAttr, error)
+	RestorePath(name string) error
+	RestoreFromTrash(name string) error
+}
+
+// restoreStorage : List the deleted paths under the given path or restore it, as per restoreOpts
+func restoreStorage(out io.Writer, storage storageRestorer, path string) error {
+	if restoreOpts.List {
+		listDeleted := storage.ListDeleted
+		if restoreOpts.Trash {
+			listDeleted = storage.ListTrash
+		}
+
+		deleted, err := listDeleted(path)
+		if err != nil {
+			return fmt.Errorf("failed to list deleted paths [%s]", err.Error())
+		}
+
+		if len(deleted) == 0 {
+			fmt.Fprintln(out, "No deleted paths found")
+			return nil
+		}
+
+		for _, attr := range deleted {
+			fmt.Fprintf(out, "%s\t%d\t%s\n", attr.Path, attr.Size, attr.Ctime.Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	}
+
+	var err error
+	if restoreOpts.Trash {
+		err = storage.RestoreFromTrash(path)
+	} else {
+		err = storage.RestorePath(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore %s [%s]", path, err.Error())
+	}
+
+	fmt.Fprintln(out, "Restored", path)
+	return nil
+}
+
+// getAzStorageComponent : Load the given config file and create a started azstorage component out of it
+func getAzStorageComponent(configFile string) (*azstorage.AzStorage, error) {
+	options.ConfigFile = configFile
+	if options.ConfigFile == "" {
+		if _, err := os.Stat(common.DefaultConfigFilePath); err == nil {
+			options.ConfigFile = common.DefaultConfigFilePath
+		}
+	}
+
+	if options.ConfigFile != "" {
+		err := parseConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	azComponent := &azstorage.AzStorage{}
+	azComponent.SetName("azstorage")
+	azComponent.SetNextComponent(nil)
+
+	err := azComponent.Configure(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure AzureStorage object [%s]", err.Error())
+	}
+
+	err = azComponent.Start(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AzureStorage object [%s]", err.Error())
+	}
+
+	return azComponent, nil
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().StringVar(&restoreOpts.ConfigFile, "config-file", "",
+		"Configures the path for the file where the account credentials are provided. Default is config.yaml in current directory.")
+	_ = restoreCmd.MarkFlagFilename("config-file", "yaml")
+
+	restoreCmd.Flags().BoolVar(&restoreOpts.List, "list", false,
+		"List soft-deleted paths under the given path instead of restoring it")
+
+	restoreCmd.Flags().BoolVar(&restoreOpts.Trash, "trash", false,
+		"Work on the files kept in trash of the container, as configured by trash-retention-days, instead of soft-deleted paths")
+}
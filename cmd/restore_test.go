@@ -0,0 +1,232 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeRestorer : Soft-deleted and trashed paths held in memory, restoring moves them to restored
+type fakeRestorer struct {
+	deleted  []*internal.ObjAttr
+	trash    []*internal.ObjAttr
+	restored []string
+}
+
+func deletedAttr(path string, size int64) *internal.ObjAttr {
+	return &internal.ObjAttr{Path: path, Size: size, Ctime: time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)}
+}
+
+func listUnder(attrs []*internal.ObjAttr, prefix string) []*internal.ObjAttr {
+	listed := make([]*internal.ObjAttr, 0)
+	for _, attr := range attrs {
+		if strings.HasPrefix(attr.Path, prefix) {
+			listed = append(listed, attr)
+		}
+	}
+	return listed
+}
+
+func restoreFrom(attrs []*internal.ObjAttr, name string) ([]*internal.ObjAttr, error) {
+	for i, attr := range attrs {
+		if attr.Path == name {
+			return append(attrs[:i], attrs[i+1:]...), nil
+		}
+	}
+	return attrs, syscall.ENOENT
+}
+
+func (f *fakeRestorer) ListDeleted(prefix string) ([]*internal.ObjAttr, error) {
+	return listUnder(f.deleted, prefix), nil
+}
+
+func (f *fakeRestorer) ListTrash(prefix string) ([]*internal.ObjAttr, error) {
+	return listUnder(f.trash, prefix), nil
+}
+
+func (f *fakeRestorer) RestorePath(name string) (err error) {
+	f.deleted, err = restoreFrom(f.deleted, name)
+	if err == nil {
+		f.restored = append(f.restored, name)
+	}
+	return err
+}
+
+func (f *fakeRestorer) RestoreFromTrash(name string) (err error) {
+	f.trash, err = restoreFrom(f.trash, name)
+	if err == nil {
+		f.restored = append(f.restored, name)
+	}
+	return err
+}
+
+// testDeleted : dir/a and b soft-deleted, dir/c in trash
+func testDeleted() *fakeRestorer {
+	return &fakeRestorer{
+		deleted: []*internal.ObjAttr{deletedAttr("dir/a", 10), deletedAttr("b", 20)},
+		trash:   []*internal.ObjAttr{deletedAttr("dir/c", 30)},
+	}
+}
+
+type restoreTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *restoreTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *restoreTestSuite) cleanupTest() {
+	restoreOpts = restoreOptions{}
+	restoreCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestRestoreCommand(t *testing.T) {
+	suite.Run(t, new(restoreTestSuite))
+}
+
+func (suite *restoreTestSuite) TestRestoreHelp() {
+	defer suite.cleanupTest()
+	_, err := executeCommandC(rootCmd, "restore", "-h")
+	suite.assert.Nil(err)
+}
+
+func (suite *restoreTestSuite) TestRestoreNoPath() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "restore")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "path to be restored not provided")
+}
+
+func (suite *restoreTestSuite) TestRestoreInvalidConfigFile() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "restore", "abc.txt", "--config-file=cfgNotFound.yaml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid config file")
+}
+
+func (suite *restoreTestSuite) TestRestoreListTooManyArgs() {
+	defer suite.cleanupTest()
+	_, err := executeCommandC(rootCmd, "restore", "--list", "a", "b")
+	suite.assert.NotNil(err)
+}
+
+func (suite *restoreTestSuite) TestRestoreList() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	restoreOpts.List = true
+	out := &bytes.Buffer{}
+
+	err := restoreStorage(out, testDeleted(), "")
+	suite.assert.Nil(err)
+	suite.assert.Equal("dir/a\t10\t2026-10-18 10:00:00\nb\t20\t2026-10-18 10:00:00\n", out.String())
+
+	out.Reset()
+	err = restoreStorage(out, testDeleted(), "dir")
+	suite.assert.Nil(err)
+	suite.assert.Equal("dir/a\t10\t2026-10-18 10:00:00\n", out.String())
+
+	out.Reset()
+	err = restoreStorage(out, testDeleted(), "missing")
+	suite.assert.Nil(err)
+	suite.assert.Equal("No deleted paths found\n", out.String())
+}
+
+func (suite *restoreTestSuite) TestRestoreListTrash() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	restoreOpts.List, restoreOpts.Trash = true, true
+	out := &bytes.Buffer{}
+
+	err := restoreStorage(out, testDeleted(), "")
+	suite.assert.Nil(err)
+	suite.assert.Equal("dir/c\t30\t2026-10-18 10:00:00\n", out.String())
+}
+
+func (suite *restoreTestSuite) TestRestorePath() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	storage := testDeleted()
+	out := &bytes.Buffer{}
+
+	err := restoreStorage(out, storage, "dir/a")
+	suite.assert.Nil(err)
+	suite.assert.Equal("Restored dir/a\n", out.String())
+	suite.assert.Equal([]string{"dir/a"}, storage.restored)
+	suite.assert.Len(storage.deleted, 1)
+
+	err = restoreStorage(out, storage, "dir/a")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "failed to restore dir/a")
+
+	// a trashed path is not soft-deleted
+	err = restoreStorage(out, storage, "dir/c")
+	suite.assert.NotNil(err)
+	suite.assert.Len(storage.trash, 1)
+}
+
+func (suite *restoreTestSuite) TestRestoreFromTrash() {
+	defer suite.cleanupTest()
+	suite.cleanupTest()
+	restoreOpts.Trash = true
+	storage := testDeleted()
+	out := &bytes.Buffer{}
+
+	err := restoreStorage(out, storage, "dir/c")
+	suite.assert.Nil(err)
+	suite.assert.Equal("Restored dir/c\n", out.String())
+	suite.assert.Equal([]string{"dir/c"}, storage.restored)
+	suite.assert.Empty(storage.trash)
+	suite.assert.Len(storage.deleted, 2)
+}
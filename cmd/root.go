@@ -46,6 +46,7 @@ import (
 	"github.com/Azure/azure-storage-fuse/v2/common"
 	"github.com/Azure/azure-storage-fuse/v2/common/log"
 
+	"github.com/sevlyar/go-daemon"
 	"github.com/spf13/cobra"
 )
 
@@ -185,7 +186,7 @@ func VersionCheck() error {
 
 // ignoreCommand : There are command implicitly added by cobra itself, while parsing we need to ignore these commands
 func ignoreCommand(cmdArgs []string) bool {
-	ignoreCmds := []string{"completion", "help"}
+	ignoreCmds := []string{"completion", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
 	if len(cmdArgs) > 0 {
 		for _, c := range ignoreCmds {
 			if c == cmdArgs[0] {
@@ -259,12 +260,20 @@ func parseArgs(cmdArgs []string) []string {
 
 // Execute : Actual command execution starts from here
 func Execute() error {
+	if isMountHelper(os.Args[0]) {
+		os.Exit(executeMountHelper(os.Args))
+	}
+
 	parsedArgs := parseArgs(os.Args)
 	rootCmd.SetArgs(parsedArgs)
 
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		// The daemonized child leaves reporting to its parent, which reads its stderr and exit code
+		if errorOutput == outputJSON && !daemon.WasReborn() {
+			printErrorReport(os.Stdout, err)
+		}
+		os.Exit(exitCode(err))
 	}
 	return err
 }
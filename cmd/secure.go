@@ -48,9 +48,11 @@ type secureOptions struct {
 	Operation  string
 	ConfigFile string
 	PassPhrase string
-	OutputFile string
-	Key        string
-	Value      string
+	// Key Vault secret or keyring key holding the passphrase, see secret.Resolve
+	PassphraseSource string
+	OutputFile       string
+	Key              string
+	Value            string
 }
 
 const SecureConfigEnvName string = "BLOBFUSE2_SECURE_CONFIG_PASSPHRASE"
@@ -81,7 +83,7 @@ var encryptCmd = &cobra.Command{
 	Short:             "Encrypt your config file",
 	Long:              "Encrypt your config file",
 	SuggestFor:        []string{"en", "enc"},
-	Example:           "blobfuse2 secure encrypt --config-file=config.yaml --passphrase=PASSPHRASE",
+	Example:           "blobfuse2 secure encrypt --config-file=config.yaml --passphrase=PASSPHRASE\nblobfuse2 secure encrypt --config-file=config.yaml --passphrase-source=keyvault://myvault/blobfuse2-passphrase",
 	FlagErrorHandling: cobra.ExitOnError,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		err := validateOptions()
@@ -123,10 +125,6 @@ var decryptCmd = &cobra.Command{
 //--------------- command section ends
 
 func validateOptions() error {
-	if secOpts.PassPhrase == "" {
-		secOpts.PassPhrase = os.Getenv(SecureConfigEnvName)
-	}
-
 	if secOpts.ConfigFile == "" {
 		return errors.New("config file not provided, check usage")
 	}
@@ -135,8 +133,14 @@ func validateOptions() error {
 		return errors.New("config file does not exist")
 	}
 
+	passphrase, err := lookupPassphrase(secOpts.PassPhrase, secOpts.PassphraseSource)
+	if err != nil {
+		return err
+	}
+	secOpts.PassPhrase = passphrase
+
 	if secOpts.PassPhrase == "" {
-		return errors.New("provide the passphrase as a cli parameter or configure the BLOBFUSE2_SECURE_CONFIG_PASSPHRASE environment variable")
+		return errors.New("provide the passphrase or its source as a cli parameter or configure the BLOBFUSE2_SECURE_CONFIG_PASSPHRASE environment variable")
 	}
 
 	return nil
@@ -237,6 +241,9 @@ func init() {
 	secureCmd.PersistentFlags().StringVar(&secOpts.PassPhrase, "passphrase", "",
 		"Key to be used for encryption / decryption. Can also be specified by env-variable BLOBFUSE2_SECURE_CONFIG_PASSPHRASE.\nKey length shall be 16 (AES-128), 24 (AES-192), or 32 (AES-256) bytes in length.")
 
+	secureCmd.PersistentFlags().StringVar(&secOpts.PassphraseSource, "passphrase-source", "",
+		"Fetch the key from Azure Key Vault, keyvault://<vault>/<secret>[?client-id=<identity>], or the keyring of the OS, keyring://<key name>.")
+
 	secureCmd.PersistentFlags().StringVar(&secOpts.OutputFile, "output-file", "",
 		"Path and name for the output file")
 }
@@ -277,3 +277,21 @@ func (suite *secureConfigTestSuite) TestSecureConfigSet() {
 	_, err = executeCommandSecure(rootCmd, "secure", "get", fmt.Sprintf("--config-file=%s", outFile.Name()), "--passphrase=123123123123123123123123", "--key=logging.level")
 	suite.assert.Nil(err)
 }
+
+func (suite *secureConfigTestSuite) TestLookupPassphrase() {
+	defer suite.cleanupTest()
+	os.Setenv(SecureConfigEnvName, "fromenv")
+	defer os.Unsetenv(SecureConfigEnvName)
+
+	passphrase, err := lookupPassphrase("fromcli", "keyring://blobfuse2")
+	suite.assert.Nil(err)
+	suite.assert.Equal("fromcli", passphrase)
+
+	passphrase, err = lookupPassphrase("", "")
+	suite.assert.Nil(err)
+	suite.assert.Equal("fromenv", passphrase)
+
+	_, err = lookupPassphrase("", "bogus://source")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "bogus://source")
+}
@@ -0,0 +1,472 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/sevlyar/go-daemon"
+	"github.com/spf13/cobra"
+)
+
+// serviceOptions : Process owning several named mounts, which blobfuse2 mount add, remove and list manage at runtime
+type serviceOptions struct {
+	Name           string         `config:"name" yaml:"name,omitempty"`
+	StopTimeoutSec uint32         `config:"stop-timeout-sec" yaml:"stop-timeout-sec,omitempty"`
+	Mounts         []serviceMount `config:"mounts" yaml:"mounts,omitempty"`
+}
+
+// serviceMount : Named mount started along with the service process
+type serviceMount struct {
+	Name       string            `config:"name" yaml:"name"`
+	MountPath  string            `config:"mount-path" yaml:"mount-path"`
+	ConfigFile string            `config:"config-file" yaml:"config-file,omitempty"`
+	Options    map[string]string `config:"options" yaml:"options,omitempty"`
+}
+
+const (
+	defaultServiceName        = "default"
+	defaultServiceStopTimeout = 30
+)
+
+// Time the service process gets to carry out a mount verb, a removal waits for the mount to go away
+const serviceRequestTimeout = (defaultServiceStopTimeout + 30) * time.Second
+
+// Set in the environment of a mount the service process runs, holds the name of the mount
+const serviceMountEnv = "BLOBFUSE2_SERVICE_MOUNT"
+
+// Names of mounts, kept to what is safe in a path or a log line
+var mountNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Options of a mount the service process sets itself
+var serviceOwnedOptions = []string{"config-file", "foreground"}
+
+var serviceCmdOpts struct {
+	configFile string
+	name       string
+}
+
+// serviceWorker : Mount process of a named mount
+type serviceWorker struct {
+	spec   admin.MountSpec
+	cmd    *exec.Cmd
+	state  string
+	reason string
+	since  time.Time
+	exited chan struct{} // closed once the mount process is gone
+}
+
+// mountService : Named mounts of the service process, each run as a blobfuse2 mount process of its own in foreground
+type mountService struct {
+	sync.Mutex
+	configFile  string // used by mounts which do not name a config file
+	stopTimeout time.Duration
+	workers     map[string]*serviceWorker
+
+	// Replaced in tests
+	start  func(spec admin.MountSpec) (*exec.Cmd, error)
+	detach func(mountPath string)
+}
+
+func newMountService(configFile string, stopTimeout time.Duration) *mountService {
+	return &mountService{
+		configFile:  configFile,
+		stopTimeout: stopTimeout,
+		workers:     make(map[string]*serviceWorker),
+		start:       serviceMountCommand,
+		detach:      clearStaleMount,
+	}
+}
+
+// normalize : Check the spec of a mount and make its paths absolute
+func (s *mountService) normalize(spec admin.MountSpec) (admin.MountSpec, error) {
+	if !mountNamePattern.MatchString(spec.Name) {
+		return spec, fmt.Errorf("invalid mount name %q, use letters, digits, '.', '_' and '-'", spec.Name)
+	}
+
+	if spec.MountPath == "" {
+		return spec, fmt.Errorf("mount path of %s not provided", spec.Name)
+	}
+	mountPath, err := filepath.Abs(common.ExpandPath(spec.MountPath))
+	if err != nil {
+		return spec, err
+	}
+	spec.MountPath = mountPath
+
+	if spec.ConfigFile == "" {
+		spec.ConfigFile = s.configFile
+	}
+	configFile, err := filepath.Abs(common.ExpandPath(spec.ConfigFile))
+	if err != nil {
+		return spec, err
+	}
+	if _, err = os.Stat(configFile); err != nil {
+		return spec, fmt.Errorf("config file of %s can not be read [%s]", spec.Name, err.Error())
+	}
+	spec.ConfigFile = configFile
+
+	for key := range spec.Options {
+		for _, owned := range serviceOwnedOptions {
+			if key == owned {
+				return spec, fmt.Errorf("option %s of %s is set by the service", key, spec.Name)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// Add : Start a named mount. A mount which failed is replaced, one which could not start is listed as failed.
+func (s *mountService) Add(spec admin.MountSpec) error {
+	spec, err := s.normalize(spec)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for name, w := range s.workers {
+		if w.state == admin.MountFailed {
+			continue
+		}
+		if name == spec.Name {
+			return fmt.Errorf("mount %s exists already", spec.Name)
+		}
+		if w.spec.MountPath == spec.MountPath {
+			return fmt.Errorf("%s is mounted by %s already", spec.MountPath, name)
+		}
+	}
+
+	w := &serviceWorker{spec: spec, state: admin.MountRunning, since: time.Now(), exited: make(chan struct{})}
+	s.workers[spec.Name] = w
+
+	w.cmd, err = s.start(spec)
+	if err != nil {
+		w.state = admin.MountFailed
+		w.reason = err.Error()
+		close(w.exited)
+		log.Err("Service::Add : failed to start %s on %s [%s]", spec.Name, spec.MountPath, err.Error())
+		return fmt.Errorf("failed to start %s [%s]", spec.Name, err.Error())
+	}
+
+	log.Info("Service::Add : started %s on %s with pid %d", spec.Name, spec.MountPath, w.cmd.Process.Pid)
+	go s.wait(w)
+	return nil
+}
+
+// wait : Mark a mount failed when its process exits before it is removed
+func (s *mountService) wait(w *serviceWorker) {
+	err := w.cmd.Wait()
+
+	s.Lock()
+	stopping := w.state == admin.MountStopping
+	if !stopping {
+		w.state = admin.MountFailed
+		w.reason = "mount process exited"
+		if err != nil {
+			w.reason = fmt.Sprintf("mount process exited [%s]", err.Error())
+		}
+		w.since = time.Now()
+		log.Err("Service::wait : %s on %s failed, %s", w.spec.Name, w.spec.MountPath, w.reason)
+	}
+	s.Unlock()
+
+	if !stopping {
+		s.detach(w.spec.MountPath)
+	}
+	close(w.exited)
+}
+
+// Remove : Unmount a named mount and forget it, a mount which does not go away in time is killed
+func (s *mountService) Remove(name string) error {
+	s.Lock()
+	w, found := s.workers[name]
+	if !found {
+		s.Unlock()
+		return fmt.Errorf("no mount named %s", name)
+	}
+
+	switch w.state {
+	case admin.MountStopping:
+		s.Unlock()
+		return fmt.Errorf("mount %s is being removed already", name)
+	case admin.MountFailed:
+		delete(s.workers, name)
+		s.Unlock()
+		return nil
+	}
+
+	w.state = admin.MountStopping
+	w.since = time.Now()
+	s.Unlock()
+
+	log.Info("Service::Remove : stopping %s on %s", name, w.spec.MountPath)
+	_ = w.cmd.Process.Signal(syscall.SIGTERM)
+
+	timer := time.NewTimer(s.stopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-w.exited:
+	case <-timer.C:
+		log.Warn("Service::Remove : %s did not stop within %s, killing it", name, s.stopTimeout)
+		_ = w.cmd.Process.Kill()
+		<-w.exited
+		s.detach(w.spec.MountPath)
+	}
+
+	s.Lock()
+	delete(s.workers, name)
+	s.Unlock()
+	return nil
+}
+
+// List : Named mounts in order of their names
+func (s *mountService) List() []admin.MountState {
+	s.Lock()
+	defer s.Unlock()
+
+	states := make([]admin.MountState, 0, len(s.workers))
+	for _, w := range s.workers {
+		state := admin.MountState{
+			MountSpec: w.spec,
+			State:     w.state,
+			Since:     w.since.Format(time.RFC3339),
+			Reason:    w.reason,
+		}
+		if w.state != admin.MountFailed && w.cmd != nil {
+			state.PID = w.cmd.Process.Pid
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+// stopAll : Remove all mounts at once
+func (s *mountService) stopAll() {
+	s.Lock()
+	names := make([]string, 0, len(s.workers))
+	for name := range s.workers {
+		names = append(names, name)
+	}
+	s.Unlock()
+
+	wg := sync.WaitGroup{}
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err := s.Remove(name)
+			if err != nil {
+				log.Err("Service::stopAll : failed to remove %s [%s]", name, err.Error())
+			}
+		}(name)
+	}
+	wg.Wait()
+}
+
+// serviceMountCommand : Run blobfuse2 mount in foreground for a named mount, options of the spec become flags
+func serviceMountCommand(spec admin.MountSpec) (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{exe, "mount", spec.MountPath,
+		"--config-file=" + spec.ConfigFile,
+		"--foreground=true",
+		"--disable-version-check=true"}
+	for _, key := range sortedKeys(spec.Options) {
+		args = append(args, fmt.Sprintf("--%s=%s", key, spec.Options[key]))
+	}
+
+	env := make([]string, 0, len(os.Environ())+1)
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if name != daemon.MARK_NAME && name != supervisedEnv && name != supervisorIncidentEnv && name != serviceMountEnv {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, fmt.Sprintf("%s=%s", serviceMountEnv, spec.Name))
+
+	cmd := &exec.Cmd{
+		Path:   exe,
+		Args:   args,
+		Env:    env,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	return cmd, cmd.Start()
+}
+
+// serviceLogger : Log as told by the logging section of the config file of the service
+func serviceLogger() error {
+	logOpts := LogOptions{Type: "syslog", LogLevel: "LOG_WARNING", LogFilePath: common.DefaultLogFilePath}
+	err := config.UnmarshalKey("logging", &logOpts)
+	if err != nil {
+		return err
+	}
+
+	var logLevel common.LogLevel
+	err = logLevel.Parse(logOpts.LogLevel)
+	if err != nil {
+		return fmt.Errorf("invalid log level [%s]", err.Error())
+	}
+
+	return log.SetDefaultLogger(logOpts.Type, common.LogConfig{
+		FilePath:    logOpts.LogFilePath,
+		MaxFileSize: logOpts.MaxLogFileSize,
+		FileCount:   logOpts.LogFileCount,
+		Level:       logLevel,
+		TimeTracker: logOpts.TimeTracker,
+		Format:      logOpts.Format,
+	})
+}
+
+// runService : Start the mounts of the config file, serve the mount verbs and remove all mounts once told to stop
+func runService() error {
+	configFile, err := filepath.Abs(common.ExpandPath(serviceCmdOpts.configFile))
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+
+	err = config.ReadFromConfigFile(configFile)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("failed to read config file %s [%s]", configFile, err.Error()))
+	}
+
+	opts := serviceOptions{}
+	err = config.UnmarshalKey("service", &opts)
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("invalid service config [%s]", err.Error()))
+	}
+	if serviceCmdOpts.name != "" {
+		opts.Name = serviceCmdOpts.name
+	}
+	if opts.Name == "" {
+		opts.Name = defaultServiceName
+	}
+	if opts.StopTimeoutSec == 0 {
+		opts.StopTimeoutSec = defaultServiceStopTimeout
+	}
+
+	err = serviceLogger()
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("failed to initialize logger [%s]", err.Error()))
+	}
+
+	s := newMountService(configFile, time.Duration(opts.StopTimeoutSec)*time.Second)
+	admin.RegisterService(s)
+	defer admin.RegisterService(nil)
+
+	err = admin.Start(admin.ServiceAddress(opts.Name))
+	if err != nil {
+		return fmt.Errorf("failed to start service %s [%s]", opts.Name, err.Error())
+	}
+	defer admin.Stop()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	log.Crit("Service::runService : service %s started with %d mounts from %s", opts.Name, len(opts.Mounts), configFile)
+
+	failed := 0
+	for _, m := range opts.Mounts {
+		err = s.Add(admin.MountSpec{Name: m.Name, MountPath: m.MountPath, ConfigFile: m.ConfigFile, Options: m.Options})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to mount %s : %s\n", m.Name, err.Error())
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Err("Service::runService : %d of %d mounts failed to start", failed, len(opts.Mounts))
+	}
+
+	sig := <-sigs
+	log.Crit("Service::runService : received %s, removing all mounts", sig.String())
+	s.stopAll()
+	return nil
+}
+
+var serviceCmd = &cobra.Command{
+	Use:               "service",
+	Short:             "Run several named mounts in one process and manage them at runtime",
+	Long:              "Run the named mounts listed in the service section of a config file, each as a blobfuse2 mount of its own in foreground, till the process is told to stop. While it runs, blobfuse2 mount add, remove and list start, stop and report its mounts. A mount which fails stays listed as failed till it is added again or removed, enable the supervisor in its config to have it mounted again instead.",
+	SuggestFor:        []string{"servce", "daemon"},
+	Example:           "blobfuse2 service --config-file=mounts.yaml\nblobfuse2 mount add logs ~/logs --config-file=logs.yaml\nblobfuse2 mount remove logs",
+	Args:              cobra.NoArgs,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runService()
+	},
+}
+
+// sendService : Send a mount verb to the service process of the given name
+func sendService(name string, req admin.Request) (admin.Response, error) {
+	if _, err := os.Stat(admin.SocketPath(admin.ServiceAddress(name))); errors.Is(err, os.ErrNotExist) {
+		return admin.Response{}, fmt.Errorf("service %s is not running", name)
+	}
+	return admin.SendTimeout(admin.ServiceAddress(name), req, serviceRequestTimeout)
+}
+
+func init() {
+	rootCmd.AddCommand(serviceCmd)
+
+	serviceCmd.Flags().StringVar(&serviceCmdOpts.configFile, "config-file", "config.yaml",
+		"Config file listing the mounts under service.mounts, also used by mounts which do not name a config file of their own.")
+	_ = serviceCmd.MarkFlagFilename("config-file", "yaml")
+	serviceCmd.Flags().StringVar(&serviceCmdOpts.name, "name", "",
+		"Name of the service, to run more than one on a node. Default is service.name of the config file or default.")
+}
@@ -0,0 +1,261 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type serviceTestSuite struct {
+	suite.Suite
+	assert     *assert.Assertions
+	configFile string
+}
+
+func (suite *serviceTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+
+	suite.configFile = filepath.Join(suite.T().TempDir(), "config.yaml")
+	err = os.WriteFile(suite.configFile, []byte("components:\n  - libfuse\n  - azstorage\n"), 0644)
+	suite.assert.Nil(err)
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(serviceTestSuite))
+}
+
+// newTestService : Service running the given shell script as the mount, records what was started and detached
+func (suite *serviceTestSuite) newTestService(script string) (*mountService, *[]admin.MountSpec, func() []string) {
+	s := newMountService(suite.configFile, 2*time.Second)
+	started := []admin.MountSpec{}
+	s.start = func(spec admin.MountSpec) (*exec.Cmd, error) {
+		started = append(started, spec)
+		worker := exec.Command("sh", "-c", script)
+		return worker, worker.Start()
+	}
+
+	mu := sync.Mutex{}
+	detached := []string{}
+	s.detach = func(mountPath string) {
+		mu.Lock()
+		defer mu.Unlock()
+		detached = append(detached, mountPath)
+	}
+	return s, &started, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, detached...)
+	}
+}
+
+func (suite *serviceTestSuite) TestAddRemove() {
+	s, started, detached := suite.newTestService("trap 'exit 0' TERM; while true; do sleep 0.1; done")
+
+	err := s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/logs", Options: map[string]string{"read-only": "true"}})
+	suite.assert.Nil(err)
+	suite.assert.Len(*started, 1)
+	suite.assert.Equal(suite.configFile, (*started)[0].ConfigFile)
+
+	// Names and mount paths are taken once
+	err = s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/other"})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "exists already")
+	err = s.Add(admin.MountSpec{Name: "other", MountPath: "/mnt/logs/"})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "mounted by logs already")
+
+	mounts := s.List()
+	suite.assert.Len(mounts, 1)
+	suite.assert.Equal("logs", mounts[0].Name)
+	suite.assert.Equal(admin.MountRunning, mounts[0].State)
+	suite.assert.NotZero(mounts[0].PID)
+
+	err = s.Remove("logs")
+	suite.assert.Nil(err)
+	suite.assert.Empty(s.List())
+	suite.assert.Empty(detached())
+
+	err = s.Remove("logs")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "no mount named logs")
+}
+
+func (suite *serviceTestSuite) TestInvalidSpec() {
+	s, started, _ := suite.newTestService("exit 0")
+
+	err := s.Add(admin.MountSpec{Name: "../logs", MountPath: "/mnt/logs"})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "invalid mount name")
+
+	err = s.Add(admin.MountSpec{Name: "logs"})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "mount path of logs not provided")
+
+	err = s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/logs", ConfigFile: "/does/not/exist.yaml"})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "config file of logs can not be read")
+
+	err = s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/logs", Options: map[string]string{"foreground": "false"}})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "set by the service")
+
+	suite.assert.Empty(*started)
+	suite.assert.Empty(s.List())
+}
+
+func (suite *serviceTestSuite) TestMountExits() {
+	s, started, detached := suite.newTestService("exit 3")
+
+	err := s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/logs"})
+	suite.assert.Nil(err)
+
+	suite.assert.Eventually(func() bool {
+		mounts := s.List()
+		return len(mounts) == 1 && mounts[0].State == admin.MountFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mounts := s.List()
+	suite.assert.Contains(mounts[0].Reason, "exit status 3")
+	suite.assert.Zero(mounts[0].PID)
+	suite.assert.Eventually(func() bool { return len(detached()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// A failed mount can be added again under the same name
+	err = s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/logs"})
+	suite.assert.Nil(err)
+	suite.assert.Len(*started, 2)
+
+	suite.assert.Eventually(func() bool {
+		mounts := s.List()
+		return len(mounts) == 1 && mounts[0].State == admin.MountFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = s.Remove("logs")
+	suite.assert.Nil(err)
+	suite.assert.Empty(s.List())
+}
+
+func (suite *serviceTestSuite) TestRemoveKillsHungMount() {
+	s, _, detached := suite.newTestService("trap '' TERM; while true; do sleep 0.1; done")
+	s.stopTimeout = 200 * time.Millisecond
+
+	err := s.Add(admin.MountSpec{Name: "logs", MountPath: "/mnt/logs"})
+	suite.assert.Nil(err)
+	time.Sleep(100 * time.Millisecond)
+
+	err = s.Remove("logs")
+	suite.assert.Nil(err)
+	suite.assert.Equal([]string{"/mnt/logs"}, detached())
+	suite.assert.Empty(s.List())
+}
+
+func (suite *serviceTestSuite) TestStopAll() {
+	s, _, _ := suite.newTestService("trap 'exit 0' TERM; while true; do sleep 0.1; done")
+
+	for _, name := range []string{"a", "b", "c"} {
+		err := s.Add(admin.MountSpec{Name: name, MountPath: "/mnt/" + name})
+		suite.assert.Nil(err)
+	}
+	suite.assert.Len(s.List(), 3)
+
+	s.stopAll()
+	suite.assert.Empty(s.List())
+}
+
+func (suite *serviceTestSuite) TestBuildMountSpec() {
+	cwd, _ := os.Getwd()
+
+	spec, err := buildMountSpec("logs", "logs", "conf.yaml", []string{"azstorage.container=logs", "--read-only=true"})
+	suite.assert.Nil(err)
+	suite.assert.Equal(filepath.Join(cwd, "logs"), spec.MountPath)
+	suite.assert.Equal(filepath.Join(cwd, "conf.yaml"), spec.ConfigFile)
+	suite.assert.Equal(map[string]string{"azstorage.container": "logs", "read-only": "true"}, spec.Options)
+
+	spec, err = buildMountSpec("logs", "/mnt/logs", "", nil)
+	suite.assert.Nil(err)
+	suite.assert.Empty(spec.ConfigFile)
+	suite.assert.Nil(spec.Options)
+
+	_, err = buildMountSpec("logs", "/mnt/logs", "", []string{"read-only"})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "shall be key=value")
+}
+
+func (suite *serviceTestSuite) TestMergeNamedMounts() {
+	named := []admin.MountState{
+		{MountSpec: admin.MountSpec{Name: "a", MountPath: "/mnt/a"}, State: admin.MountRunning},
+		{MountSpec: admin.MountSpec{Name: "b", MountPath: "/mnt/b"}, State: admin.MountFailed, Reason: "mount process exited"},
+	}
+
+	mounts := mergeNamedMounts([]string{"/mnt/x", "/mnt/a"}, named)
+	suite.assert.Len(mounts, 3)
+	suite.assert.Equal("/mnt/x", mounts[0].path)
+	suite.assert.Nil(mounts[0].named)
+	suite.assert.Equal("/mnt/a", mounts[1].path)
+	suite.assert.Equal("a", mounts[1].named.Name)
+	suite.assert.Equal("/mnt/b", mounts[2].path)
+	suite.assert.Equal("b", mounts[2].named.Name)
+
+	// Failed mounts are not asked for their status
+	info := mounts[2].info(time.Now())
+	suite.assert.Equal(admin.HealthUnresponsive, info.Health)
+	suite.assert.Equal([]string{"mount process exited"}, info.Reasons)
+	suite.assert.Equal("b", info.Name)
+	suite.assert.Equal(admin.MountFailed, info.ServiceState)
+}
+
+func (suite *serviceTestSuite) TestServiceNotRunning() {
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	_, err := sendService("default", admin.Request{Verb: admin.VerbMountList})
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "service default is not running")
+}
@@ -0,0 +1,150 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+)
+
+// Time modified files get to upload when the mount is told to stop, below the 30 seconds most container runtimes wait
+const defaultShutdownTimeout = 25
+
+// handleShutdown : On SIGTERM or SIGINT upload the modified files and unmount, so the pipeline stops and disposes of its
+// cache before the process exits. This is how container runtimes and service managers stop a mount. A second signal
+// exits at once. The returned function stops handling the signals.
+func handleShutdown(mountPath string) func() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-signals:
+			go func() {
+				select {
+				case <-signals:
+					log.Crit("Mount::handleShutdown : second signal received, exiting without clean shutdown")
+					_ = log.Destroy()
+					os.Exit(1)
+				case <-done:
+				}
+			}()
+
+			err := shutdownMount(mountPath, sig)
+			if err != nil {
+				log.Err("Mount::handleShutdown : %s", err.Error())
+				_ = log.Destroy()
+				os.Exit(1)
+			}
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// shutdownMount : Upload modified files, settle the cache disposition and unmount. The fuse session ends with the
+// unmount, after which the pipeline is stopped the same way as for a regular unmount.
+func shutdownMount(mountPath string, sig os.Signal) error {
+	log.Crit("Mount::shutdownMount : %s received, shutting down %s", sig.String(), mountPath)
+
+	timeout := options.ShutdownTimeoutSec
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	resp := admin.Dispatch(admin.Request{Verb: admin.VerbSync, TimeoutSec: int(timeout)})
+	if resp.Sync != nil {
+		log.Crit("Mount::shutdownMount : %d modified files uploaded", resp.Sync.Uploaded)
+		for _, failure := range resp.Sync.Failures {
+			log.Err("Mount::shutdownMount : failed to upload %s [%s]", failure.Path, failure.Reason)
+		}
+	} else if resp.Error != "" && !strings.Contains(resp.Error, "not supported") {
+		log.Err("Mount::shutdownMount : failed to upload modified files [%s]", resp.Error)
+	}
+
+	if options.ShutdownCache != "" {
+		resp = admin.Dispatch(admin.Request{Verb: admin.VerbUnmount, Cache: options.ShutdownCache})
+		if resp.Error != "" {
+			log.Err("Mount::shutdownMount : failed to %s cache [%s]", options.ShutdownCache, resp.Error)
+		}
+	}
+
+	err := unmountForShutdown(mountPath)
+	if err != nil {
+		return fmt.Errorf("failed to unmount %s [%s]", mountPath, err.Error())
+	}
+	return nil
+}
+
+// unmountForShutdown : Unmount through the fuse helper, lazily when the mount is busy since the process goes away either way
+func unmountForShutdown(mountPath string) error {
+	for _, export := range common.ListExports(mountPath) {
+		clearStaleMount(export)
+	}
+
+	if !common.IsDirectoryMounted(mountPath) {
+		return nil
+	}
+
+	for _, helper := range []string{"fusermount3", "fusermount"} {
+		out, err := exec.Command(helper, "-u", mountPath).CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, exec.ErrNotFound) {
+			log.Warn("Mount::unmountForShutdown : %s -u %s failed [%s] %s", helper, mountPath, err.Error(), string(out))
+			break
+		}
+	}
+
+	clearStaleMount(mountPath)
+	if common.IsDirectoryMounted(mountPath) {
+		return errors.New("mount point is still mounted")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type shutdownTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *shutdownTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	options = mountOptions{}
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *shutdownTestSuite) cleanupTest() {
+	options = mountOptions{}
+	admin.UnregisterSync("test")
+	admin.UnregisterHandler(admin.VerbUnmount, "test")
+}
+
+func TestShutdown(t *testing.T) {
+	suite.Run(t, new(shutdownTestSuite))
+}
+
+func (suite *shutdownTestSuite) TestLogFilePath() {
+	suite.assert.Equal("stdout", logFilePath("stdout"))
+	suite.assert.Equal("/var/log/blobfuse2.log", logFilePath("/var/log/blobfuse2.log"))
+}
+
+func (suite *shutdownTestSuite) TestShutdownMount() {
+	defer suite.cleanupTest()
+	options.ShutdownTimeoutSec = 5
+	options.ShutdownCache = admin.CacheCleanup
+
+	var deadline time.Time
+	admin.RegisterSync("test", func(req admin.Request, until time.Time) admin.SyncReport {
+		deadline = until
+		return admin.SyncReport{Uploaded: 2, Failures: []admin.SyncFailure{{Path: "a", Reason: "storage unavailable"}}}
+	})
+
+	disposition := ""
+	admin.RegisterHandler(admin.VerbUnmount, "test", func(req admin.Request) (string, error) {
+		disposition = req.Cache
+		return "", nil
+	})
+
+	start := time.Now()
+	err := shutdownMount(suite.T().TempDir(), syscall.SIGTERM)
+	suite.assert.Nil(err)
+	suite.assert.WithinDuration(start.Add(5*time.Second), deadline, time.Second)
+	suite.assert.Equal(admin.CacheCleanup, disposition)
+}
+
+func (suite *shutdownTestSuite) TestShutdownMountDefaults() {
+	defer suite.cleanupTest()
+
+	var deadline time.Time
+	admin.RegisterSync("test", func(req admin.Request, until time.Time) admin.SyncReport {
+		deadline = until
+		return admin.SyncReport{}
+	})
+
+	disposition := "unset"
+	admin.RegisterHandler(admin.VerbUnmount, "test", func(req admin.Request) (string, error) {
+		disposition = req.Cache
+		return "", nil
+	})
+
+	start := time.Now()
+	err := shutdownMount(suite.T().TempDir(), syscall.SIGINT)
+	suite.assert.Nil(err)
+	suite.assert.WithinDuration(start.Add(defaultShutdownTimeout*time.Second), deadline, time.Second)
+
+	// Cache is left to what the file cache is configured to do
+	suite.assert.Equal("unset", disposition)
+}
+
+func (suite *shutdownTestSuite) TestShutdownMountWithoutFileCache() {
+	defer suite.cleanupTest()
+
+	err := shutdownMount(suite.T().TempDir(), syscall.SIGTERM)
+	suite.assert.Nil(err)
+}
+
+func (suite *shutdownTestSuite) TestHandleShutdownStop() {
+	defer suite.cleanupTest()
+
+	stop := handleShutdown(suite.T().TempDir())
+	stop()
+}
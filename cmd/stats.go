@@ -0,0 +1,209 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+type statsOptions struct {
+	Output   string
+	Interval time.Duration
+	Count    uint32
+}
+
+var statsOpts statsOptions
+
+// mountStats : Metrics of a mount over one interval, derived from two reports of its counters
+type mountStats struct {
+	MountPath      string                      `json:"mountPath"`
+	Time           string                      `json:"time"`
+	IntervalSec    float64                     `json:"intervalSec"`
+	ReadMBps       float64                     `json:"readMBps"`
+	WriteMBps      float64                     `json:"writeMBps"`
+	HitRatio       map[string]float64          `json:"hitRatio,omitempty"` // component -> percentage since mount
+	UploadsPending int64                       `json:"uploadsPending"`
+	StorageErrors  int64                       `json:"storageErrors"`
+	Counters       map[string]map[string]int64 `json:"counters"`
+}
+
+var statsCmd = &cobra.Command{
+	Use:               "stats <mount path>",
+	Short:             "Show live metrics of a running mount",
+	Long:              "Show live metrics of a running mount: read and write throughput, cache hit ratios, uploads pending and storage errors, along with the counters of each component. Metrics are taken from the mount over its admin socket.",
+	SuggestFor:        []string{"stat", "metrics"},
+	Example:           "blobfuse2 stats ~/mount_path\nblobfuse2 stats ~/mount_path --output=json --interval=5s --count=0",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeMountPaths,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statsOpts.Output != "table" && statsOpts.Output != "json" {
+			return fmt.Errorf("invalid output format %s, shall be table or json", statsOpts.Output)
+		}
+
+		if statsOpts.Interval <= 0 {
+			return fmt.Errorf("interval shall be greater than zero")
+		}
+
+		mountPath, _, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		prev, err := fetchStats(mountPath)
+		if err != nil {
+			return err
+		}
+		prevTime := time.Now()
+
+		for i := uint32(0); statsOpts.Count == 0 || i < statsOpts.Count; i++ {
+			time.Sleep(statsOpts.Interval)
+
+			cur, err := fetchStats(mountPath)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+
+			stats := deriveStats(mountPath, prev, cur, now.Sub(prevTime))
+			stats.Time = now.Format(time.RFC3339)
+			if statsOpts.Output == "json" {
+				err = json.NewEncoder(cmd.OutOrStdout()).Encode(stats)
+			} else {
+				err = printStats(cmd.OutOrStdout(), stats)
+			}
+			if err != nil {
+				return err
+			}
+
+			prev, prevTime = cur, now
+		}
+
+		return nil
+	},
+}
+
+// fetchStats : Counters of all components of the mount
+func fetchStats(mountPath string) (map[string]map[string]int64, error) {
+	resp, err := admin.Send(mountPath, admin.Request{Verb: admin.VerbStats})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats of %s [%s]", mountPath, err.Error())
+	}
+	return resp.Stats, nil
+}
+
+// deriveStats : Throughput over the interval between two reports, the rest as of the latest report
+func deriveStats(mountPath string, prev map[string]map[string]int64, cur map[string]map[string]int64, elapsed time.Duration) mountStats {
+	stats := mountStats{
+		MountPath:   mountPath,
+		IntervalSec: elapsed.Seconds(),
+		HitRatio:    make(map[string]float64),
+		Counters:    cur,
+	}
+
+	var read, written int64
+	for component, counters := range cur {
+		read += counters[admin.StatBytesRead] - prev[component][admin.StatBytesRead]
+		written += counters[admin.StatBytesWritten] - prev[component][admin.StatBytesWritten]
+		stats.UploadsPending += counters[admin.StatUploadsPending]
+		stats.StorageErrors += counters[admin.StatStorageErrors]
+
+		hits := counters[admin.StatCacheHits]
+		if total := hits + counters[admin.StatCacheMisses]; total > 0 {
+			stats.HitRatio[component] = float64(hits) * 100 / float64(total)
+		}
+	}
+
+	if elapsed > 0 {
+		stats.ReadMBps = float64(read) / float64(common.MbToBytes) / elapsed.Seconds()
+		stats.WriteMBps = float64(written) / float64(common.MbToBytes) / elapsed.Seconds()
+	}
+
+	return stats
+}
+
+// printStats : Summary of the mount followed by the counters of each component
+func printStats(out io.Writer, stats mountStats) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Mount\t%s\n", stats.MountPath)
+	fmt.Fprintf(w, "Time\t%s\n", stats.Time)
+	fmt.Fprintf(w, "Read\t%.2f MB/s\n", stats.ReadMBps)
+	fmt.Fprintf(w, "Write\t%.2f MB/s\n", stats.WriteMBps)
+	fmt.Fprintf(w, "Uploads pending\t%d\n", stats.UploadsPending)
+	fmt.Fprintf(w, "Storage errors\t%d\n", stats.StorageErrors)
+	for _, component := range sortedKeys(stats.HitRatio) {
+		fmt.Fprintf(w, "Hit ratio %s\t%.2f%%\n", component, stats.HitRatio[component])
+	}
+
+	fmt.Fprintf(w, "\nCOMPONENT\tCOUNTER\tVALUE\n")
+	for _, component := range sortedKeys(stats.Counters) {
+		for _, counter := range sortedKeys(stats.Counters[component]) {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", component, counter, stats.Counters[component][counter])
+		}
+	}
+	fmt.Fprintln(w)
+
+	return w.Flush()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsOpts.Output, "output", "table",
+		"Output format, table or json")
+	statsCmd.Flags().DurationVar(&statsOpts.Interval, "interval", time.Second,
+		"Time between reports, throughput is measured over it")
+	statsCmd.Flags().Uint32Var(&statsOpts.Count, "count", 1,
+		"Number of reports to print, 0 keeps reporting until interrupted")
+}
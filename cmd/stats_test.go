@@ -0,0 +1,142 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type statsCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *statsCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *statsCmdTestSuite) cleanupTest() {
+	statsOpts = statsOptions{}
+	listMountPoints = common.ListMountPoints
+	statsCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestStatsCommand(t *testing.T) {
+	suite.Run(t, new(statsCmdTestSuite))
+}
+
+func (suite *statsCmdTestSuite) TestStatsInvalidOutput() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "stats", suite.T().TempDir(), "--output=xml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid output format")
+}
+
+func (suite *statsCmdTestSuite) TestDeriveStats() {
+	prev := map[string]map[string]int64{
+		"libfuse": {admin.StatBytesRead: 0, admin.StatBytesWritten: 0},
+	}
+	cur := map[string]map[string]int64{
+		"libfuse":    {admin.StatBytesRead: 4 * common.MbToBytes, admin.StatBytesWritten: common.MbToBytes},
+		"file_cache": {admin.StatCacheHits: 3, admin.StatCacheMisses: 1, admin.StatUploadsPending: 2},
+		"azstorage":  {admin.StatStorageRequests: 10, admin.StatStorageErrors: 1},
+		"attr_cache": {admin.StatCacheHits: 0, admin.StatCacheMisses: 0},
+	}
+
+	stats := deriveStats("/mnt", prev, cur, 2*time.Second)
+	suite.assert.InDelta(2, stats.ReadMBps, 0.001)
+	suite.assert.InDelta(0.5, stats.WriteMBps, 0.001)
+	suite.assert.Equal(map[string]float64{"file_cache": 75}, stats.HitRatio)
+	suite.assert.EqualValues(2, stats.UploadsPending)
+	suite.assert.EqualValues(1, stats.StorageErrors)
+}
+
+func (suite *statsCmdTestSuite) TestStats() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	read := int64(0)
+	admin.RegisterStats("libfuse", func() map[string]int64 {
+		read += common.MbToBytes
+		return map[string]int64{admin.StatBytesRead: read}
+	})
+	defer admin.UnregisterStats("libfuse")
+
+	// Mount is not serving requests yet
+	_, err := executeCommandC(rootCmd, "stats", mountPath)
+	suite.assert.NotNil(err)
+
+	err = admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	op, err := executeCommandC(rootCmd, "stats", mountPath, "--output=json", "--interval=10ms", "--count=2")
+	suite.assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(op), "\n")
+	suite.assert.Len(lines, 2)
+	stats := mountStats{}
+	err = json.Unmarshal([]byte(lines[1]), &stats)
+	suite.assert.Nil(err)
+	suite.assert.Equal(mountPath, stats.MountPath)
+	suite.assert.Greater(stats.ReadMBps, float64(0))
+
+	op, err = executeCommandC(rootCmd, "stats", mountPath, "--output=table", "--interval=10ms", "--count=1")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "libfuse")
+	suite.assert.Contains(op, admin.StatBytesRead)
+}
@@ -0,0 +1,478 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+	"github.com/Azure/azure-storage-fuse/v2/internal/stats_manager"
+
+	"github.com/sevlyar/go-daemon"
+)
+
+// supervisorOptions : Parent process watching over the mount, which it mounts again when it crashed or hung
+type supervisorOptions struct {
+	Enabled          bool   `config:"enabled" yaml:"enabled,omitempty"`
+	CheckIntervalSec uint32 `config:"check-interval-sec" yaml:"check-interval-sec,omitempty"`
+	HangTimeoutSec   uint32 `config:"hang-timeout-sec" yaml:"hang-timeout-sec,omitempty"`
+	MaxRestarts      uint32 `config:"max-restarts" yaml:"max-restarts,omitempty"`
+	BackoffSec       uint32 `config:"backoff-sec" yaml:"backoff-sec,omitempty"`
+	MaxBackoffSec    uint32 `config:"max-backoff-sec" yaml:"max-backoff-sec,omitempty"`
+}
+
+const (
+	defaultSupervisorCheckInterval = 10
+	defaultSupervisorHangTimeout   = 60
+	defaultSupervisorMaxRestarts   = 5
+	defaultSupervisorBackoff       = 2
+	defaultSupervisorMaxBackoff    = 300
+)
+
+// Restarts are counted anew once the mount stayed healthy this long
+const supervisorStablePeriod = 10 * time.Minute
+
+// Set in the environment of the mount a supervisor runs, holds the number of times it was mounted again
+const supervisedEnv = "BLOBFUSE2_SUPERVISED"
+
+// Set in the environment of the mount a supervisor runs again, holds the incident which made it do so
+const supervisorIncidentEnv = "BLOBFUSE2_SUPERVISOR_INCIDENT"
+
+// What made the supervisor mount again
+const (
+	incidentCrash = "crash" // mount process exited with an error or was killed
+	incidentHang  = "hang"  // file system operations or health checks did not complete
+	incidentLost  = "lost"  // connection to the kernel was lost and not restored by the mount itself
+)
+
+var errMountLost = errors.New("mount point is not connected")
+
+// supervisorIncident : Reported to the health monitor by the mount which replaces the one the incident happened to
+type supervisorIncident struct {
+	Kind     string `json:"kind"`
+	Reason   string `json:"reason"`
+	Time     string `json:"time"`
+	Restarts uint32 `json:"restarts"`
+}
+
+type supervisor struct {
+	opts         supervisorOptions
+	mountPath    string
+	controlFile  string // empty when the control file is disabled
+	notifyParent bool   // parent of a daemonized supervisor waits to learn the mount is up
+
+	restarts uint32
+	incident *supervisorIncident
+	probing  chan error // probe of the file system still in flight
+
+	// Replaced in tests
+	start func(restarts uint32, incident *supervisorIncident) (*exec.Cmd, error)
+	check func(worker *exec.Cmd) error
+}
+
+// superviseMount : Run the mount in a child and mount again, with backoff, when it crashes or hangs
+func superviseMount(_ context.Context) error {
+	s := newSupervisor(options.MountPath, options.Supervisor)
+	s.notifyParent = daemon.WasReborn()
+
+	s.controlFile = ".blobfuse2"
+	if config.IsSet("libfuse.control-file") {
+		_ = config.UnmarshalKey("libfuse.control-file", &s.controlFile)
+	}
+	disabled := false
+	_ = config.UnmarshalKey("libfuse.disable-control-file", &disabled)
+	if disabled {
+		s.controlFile = ""
+	}
+
+	log.Crit("Supervisor::superviseMount : supervising mount of %s, checks every %ds, hang after %ds", s.mountPath, s.opts.CheckIntervalSec, s.opts.HangTimeoutSec)
+	err := s.run()
+	_ = log.Destroy()
+	return err
+}
+
+func newSupervisor(mountPath string, opts supervisorOptions) *supervisor {
+	if opts.CheckIntervalSec == 0 {
+		opts.CheckIntervalSec = defaultSupervisorCheckInterval
+	}
+	if opts.HangTimeoutSec == 0 {
+		opts.HangTimeoutSec = defaultSupervisorHangTimeout
+	}
+	if !config.IsSet("supervisor.max-restarts") {
+		opts.MaxRestarts = defaultSupervisorMaxRestarts
+	}
+	if opts.BackoffSec == 0 {
+		opts.BackoffSec = defaultSupervisorBackoff
+	}
+	if opts.MaxBackoffSec == 0 {
+		opts.MaxBackoffSec = defaultSupervisorMaxBackoff
+	}
+
+	s := &supervisor{opts: opts, mountPath: mountPath, start: startWorker}
+	s.check = s.checkMount
+	return s
+}
+
+// run : Mount again after every incident till the mount ends on its own, a signal stops it or restarts are used up
+func (s *supervisor) run() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		kind, err := s.runWorker(signals)
+		if err != nil || kind == "" {
+			return err
+		}
+
+		clearStaleMount(s.mountPath)
+
+		if s.opts.MaxRestarts > 0 && s.restarts >= s.opts.MaxRestarts {
+			log.Err("Supervisor::run : giving up on %s after %d restarts", s.mountPath, s.restarts)
+			return fmt.Errorf("mount of %s ended by %s, giving up after %d restarts", s.mountPath, kind, s.restarts)
+		}
+
+		s.restarts++
+		wait := s.backoff()
+		log.Warn("Supervisor::run : mounting %s again in %v, restart %d", s.mountPath, wait, s.restarts)
+
+		select {
+		case <-time.After(wait):
+		case sig := <-signals:
+			if sig != syscall.SIGHUP {
+				log.Info("Supervisor::run : %v received, not mounting %s again", sig, s.mountPath)
+				return nil
+			}
+		}
+	}
+}
+
+// backoff : Wait before the current restart, doubling with each restart up to the limit
+func (s *supervisor) backoff() time.Duration {
+	wait := time.Duration(s.opts.BackoffSec) * time.Second
+	limit := time.Duration(s.opts.MaxBackoffSec) * time.Second
+	for i := uint32(1); i < s.restarts && wait < limit; i++ {
+		wait *= 2
+	}
+	if wait > limit {
+		wait = limit
+	}
+	return wait
+}
+
+// runWorker : Start the mount and watch it till it exits. Returns the kind of incident which ended it, nothing when
+// it ended on its own or was told to.
+func (s *supervisor) runWorker(signals <-chan os.Signal) (string, error) {
+	worker, err := s.start(s.restarts, s.incident)
+	if err != nil {
+		return "", fmt.Errorf("failed to start mount [%s]", err.Error())
+	}
+	log.Info("Supervisor::runWorker : mount of %s running as %d", s.mountPath, worker.Process.Pid)
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- worker.Wait()
+	}()
+
+	started := time.Now()
+	hangTimeout := time.Duration(s.opts.HangTimeoutSec) * time.Second
+	var healthySince, failingSince time.Time
+	mounted := false
+	s.probing = nil
+
+	ticker := time.NewTicker(time.Duration(s.opts.CheckIntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case sig := <-signals:
+			_ = worker.Process.Signal(sig)
+			if sig == syscall.SIGHUP {
+				continue
+			}
+			log.Info("Supervisor::runWorker : %v forwarded to mount of %s", sig, s.mountPath)
+			select {
+			case <-exited:
+			case <-time.After(hangTimeout):
+				_ = worker.Process.Kill()
+				<-exited
+				clearStaleMount(s.mountPath)
+			}
+			return "", nil
+
+		case err := <-exited:
+			if err == nil {
+				log.Info("Supervisor::runWorker : mount of %s ended", s.mountPath)
+				return "", nil
+			}
+			if !mounted && s.restarts == 0 {
+				// Nothing to recover when the very first mount fails, e.g. on an invalid config
+				return "", fmt.Errorf("mount of %s failed [%s]", s.mountPath, err.Error())
+			}
+			s.report(incidentCrash, err.Error())
+			return incidentCrash, nil
+
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		err := s.check(worker)
+		if err == nil {
+			failingSince = time.Time{}
+			if !mounted {
+				mounted, healthySince = true, now
+				s.mounted()
+			} else if s.restarts > 0 && now.Sub(healthySince) >= supervisorStablePeriod {
+				log.Info("Supervisor::runWorker : mount of %s stable again after %d restarts", s.mountPath, s.restarts)
+				s.restarts = 0
+			}
+			continue
+		}
+
+		if failingSince.IsZero() {
+			failingSince = now
+			log.Warn("Supervisor::runWorker : mount of %s not healthy [%s]", s.mountPath, err.Error())
+		}
+
+		// A mount coming up gets the same time to answer, counted from its start
+		since := failingSince
+		if !mounted {
+			since = started
+		}
+		if now.Sub(since) < hangTimeout {
+			continue
+		}
+
+		kind := incidentHang
+		if errors.Is(err, errMountLost) {
+			kind = incidentLost
+		}
+		s.report(kind, err.Error())
+
+		_ = worker.Process.Kill()
+		<-exited
+		return kind, nil
+	}
+}
+
+// mounted : Mount is up and answering, the parent of a daemonized supervisor is waiting for this
+func (s *supervisor) mounted() {
+	log.Info("Supervisor::mounted : %s is mounted", s.mountPath)
+	if s.notifyParent {
+		s.notifyParent = false
+		err := common.NotifyMountToParent()
+		if err != nil {
+			log.Err("Supervisor::mounted : failed to notify parent [%s]", err.Error())
+		}
+	}
+}
+
+// report : Log the incident, the next mount hands it to the health monitor
+func (s *supervisor) report(kind string, reason string) {
+	log.Err("Supervisor::report : %s of %s [%s]", kind, s.mountPath, reason)
+	s.incident = &supervisorIncident{
+		Kind:     kind,
+		Reason:   reason,
+		Time:     time.Now().Format(time.RFC3339),
+		Restarts: s.restarts + 1,
+	}
+}
+
+// checkMount : Whether the mount is up and answers, both through the file system and through its admin socket
+func (s *supervisor) checkMount(_ *exec.Cmd) error {
+	if !common.IsDirectoryMounted(s.mountPath) {
+		return errors.New("not mounted")
+	}
+
+	timeout := time.Duration(s.opts.CheckIntervalSec) * time.Second
+	err := s.probeMount(timeout)
+	if err != nil {
+		return err
+	}
+
+	_, err = admin.SendTimeout(s.mountPath, admin.Request{Verb: admin.VerbStatus}, timeout)
+	if err != nil {
+		return fmt.Errorf("health check failed [%s]", err.Error())
+	}
+	return nil
+}
+
+// probeMount : Have the mount serve a request, a probe which does not complete is waited for by the next check
+func (s *supervisor) probeMount(timeout time.Duration) error {
+	if s.probing == nil {
+		result := make(chan error, 1)
+		go func() {
+			result <- probeFileSystem(s.mountPath, s.controlFile)
+		}()
+		s.probing = result
+	}
+
+	select {
+	case err := <-s.probing:
+		s.probing = nil
+		return err
+	case <-time.After(timeout):
+		return errors.New("file system operations do not complete")
+	}
+}
+
+// probeFileSystem : Stat the mount point and open the control file, which the mount serves without storage
+func probeFileSystem(mountPath string, controlFile string) error {
+	_, err := os.Stat(mountPath)
+	if errors.Is(err, syscall.ENOTCONN) || errors.Is(err, syscall.ENODEV) {
+		return errMountLost
+	}
+	if err != nil || controlFile == "" {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(mountPath, controlFile))
+	if errors.Is(err, syscall.ENOTCONN) || errors.Is(err, syscall.ENODEV) {
+		return errMountLost
+	}
+	if err != nil {
+		// The answer is what counts, not whether the control file is there
+		return nil
+	}
+	return f.Close()
+}
+
+// startWorker : Run the mount command again in a child, which mounts in foreground as it is told it is supervised
+func startWorker(restarts uint32, incident *supervisorIncident) (*exec.Cmd, error) {
+	worker, err := workerCommand(restarts, incident)
+	if err != nil {
+		return nil, err
+	}
+	return worker, worker.Start()
+}
+
+// workerCommand : Mount command as the supervisor was started with, along with what the mount has to know
+func workerCommand(restarts uint32, incident *supervisorIncident) (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	env := make([]string, 0, len(os.Environ())+2)
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if name != daemon.MARK_NAME && name != supervisedEnv && name != supervisorIncidentEnv {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, fmt.Sprintf("%s=%d", supervisedEnv, restarts))
+	if incident != nil {
+		data, _ := json.Marshal(incident)
+		env = append(env, fmt.Sprintf("%s=%s", supervisorIncidentEnv, data))
+	}
+
+	// Arguments are passed on as they came, so a mount through the mount helper is parsed the same way again
+	return &exec.Cmd{
+		Path:   exe,
+		Args:   os.Args,
+		Env:    env,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}, nil
+}
+
+// clearStaleMount : Detach what a mount which was killed left behind, so the mount point can be mounted again
+func clearStaleMount(mountPath string) {
+	if !common.IsDirectoryMounted(mountPath) {
+		return
+	}
+
+	for _, helper := range []string{"fusermount3", "fusermount"} {
+		out, err := exec.Command(helper, "-u", "-z", mountPath).CombinedOutput()
+		if err == nil {
+			log.Info("Supervisor::clearStaleMount : detached %s", mountPath)
+			return
+		}
+		if !errors.Is(err, exec.ErrNotFound) {
+			log.Warn("Supervisor::clearStaleMount : %s -u -z %s failed [%s] %s", helper, mountPath, err.Error(), string(out))
+			break
+		}
+	}
+
+	err := unmountDirect(mountPath, true, false)
+	if err != nil {
+		log.Err("Supervisor::clearStaleMount : failed to detach %s [%s]", mountPath, err.Error())
+	}
+}
+
+// supervisedRestarts : Times a supervisor mounted again before this mount, false when the mount is not supervised
+func supervisedRestarts() (uint32, bool) {
+	value, found := os.LookupEnv(supervisedEnv)
+	if !found {
+		return 0, false
+	}
+	restarts, _ := strconv.ParseUint(value, 10, 32)
+	return uint32(restarts), true
+}
+
+// reportSupervisorIncident : Tell the health monitor why the supervisor had to mount again
+func reportSupervisorIncident() {
+	data := os.Getenv(supervisorIncidentEnv)
+	if data == "" {
+		return
+	}
+
+	incident := supervisorIncident{}
+	err := json.Unmarshal([]byte(data), &incident)
+	if err != nil {
+		log.Err("Mount::reportSupervisorIncident : invalid incident %s [%s]", data, err.Error())
+		return
+	}
+
+	log.Warn("Mount::reportSupervisorIncident : mounted again after %s at %s [%s]", incident.Kind, incident.Time, incident.Reason)
+	stats_manager.NewStatsCollector("supervisor").PushEvents(incident.Kind, options.MountPath, map[string]interface{}{
+		"reason":   incident.Reason,
+		"time":     incident.Time,
+		"restarts": incident.Restarts,
+	})
+}
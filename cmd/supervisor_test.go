@@ -0,0 +1,186 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type supervisorTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *supervisorTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func TestSupervisor(t *testing.T) {
+	suite.Run(t, new(supervisorTestSuite))
+}
+
+// newTestSupervisor : Supervisor running the given shell script as the mount, checking every second
+func (suite *supervisorTestSuite) newTestSupervisor(script string, check func(*exec.Cmd) error) (*supervisor, *int) {
+	s := newSupervisor(suite.T().TempDir(), supervisorOptions{CheckIntervalSec: 1, HangTimeoutSec: 1, BackoffSec: 1, MaxBackoffSec: 1})
+	started := 0
+	s.start = func(restarts uint32, incident *supervisorIncident) (*exec.Cmd, error) {
+		started++
+		worker := exec.Command("sh", "-c", script)
+		return worker, worker.Start()
+	}
+	s.check = check
+	return s, &started
+}
+
+func (suite *supervisorTestSuite) TestDefaults() {
+	s := newSupervisor("/tmp/mnt", supervisorOptions{})
+	suite.assert.EqualValues(defaultSupervisorCheckInterval, s.opts.CheckIntervalSec)
+	suite.assert.EqualValues(defaultSupervisorHangTimeout, s.opts.HangTimeoutSec)
+	suite.assert.EqualValues(defaultSupervisorMaxRestarts, s.opts.MaxRestarts)
+	suite.assert.EqualValues(defaultSupervisorBackoff, s.opts.BackoffSec)
+	suite.assert.EqualValues(defaultSupervisorMaxBackoff, s.opts.MaxBackoffSec)
+}
+
+func (suite *supervisorTestSuite) TestBackoff() {
+	s := newSupervisor("/tmp/mnt", supervisorOptions{BackoffSec: 2, MaxBackoffSec: 10})
+
+	expected := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, wait := range expected {
+		s.restarts = uint32(i + 1)
+		suite.assert.Equal(wait, s.backoff(), "restart %d", s.restarts)
+	}
+}
+
+func (suite *supervisorTestSuite) TestFirstMountFails() {
+	s, started := suite.newTestSupervisor("exit 1", func(*exec.Cmd) error { return nil })
+
+	err := s.run()
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "failed")
+	suite.assert.Equal(1, *started)
+	suite.assert.Nil(s.incident)
+}
+
+func (suite *supervisorTestSuite) TestMountEnds() {
+	s, started := suite.newTestSupervisor("exit 0", func(*exec.Cmd) error { return nil })
+
+	err := s.run()
+	suite.assert.Nil(err)
+	suite.assert.Equal(1, *started)
+}
+
+func (suite *supervisorTestSuite) TestCrash() {
+	// Mounts fine, as far as the checks tell, then crashes
+	s, started := suite.newTestSupervisor("sleep 2; exit 3", func(*exec.Cmd) error { return nil })
+	s.opts.MaxRestarts = 1
+
+	err := s.run()
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "giving up after 1 restarts")
+	suite.assert.Equal(2, *started)
+	suite.assert.NotNil(s.incident)
+	suite.assert.Equal(incidentCrash, s.incident.Kind)
+	suite.assert.EqualValues(2, s.incident.Restarts)
+}
+
+func (suite *supervisorTestSuite) TestHang() {
+	s, started := suite.newTestSupervisor("sleep 60", func(*exec.Cmd) error {
+		return errors.New("file system operations do not complete")
+	})
+	s.opts.MaxRestarts = 1
+
+	begin := time.Now()
+	err := s.run()
+	suite.assert.NotNil(err)
+	suite.assert.Equal(2, *started)
+	suite.assert.Equal(incidentHang, s.incident.Kind)
+	suite.assert.Contains(s.incident.Reason, "do not complete")
+	suite.assert.Less(time.Since(begin), 30*time.Second)
+}
+
+func (suite *supervisorTestSuite) TestLost() {
+	s, _ := suite.newTestSupervisor("sleep 60", func(*exec.Cmd) error { return errMountLost })
+	s.opts.MaxRestarts = 1
+
+	err := s.run()
+	suite.assert.NotNil(err)
+	suite.assert.Equal(incidentLost, s.incident.Kind)
+}
+
+func (suite *supervisorTestSuite) TestSupervisedRestarts() {
+	restarts, supervised := supervisedRestarts()
+	suite.assert.False(supervised)
+	suite.assert.EqualValues(0, restarts)
+
+	suite.T().Setenv(supervisedEnv, "3")
+	restarts, supervised = supervisedRestarts()
+	suite.assert.True(supervised)
+	suite.assert.EqualValues(3, restarts)
+}
+
+func (suite *supervisorTestSuite) TestWorkerCommand() {
+	incident := &supervisorIncident{Kind: incidentHang, Reason: "health check failed", Time: "2026-10-18T10:00:00Z", Restarts: 2}
+	suite.T().Setenv(supervisorIncidentEnv, "stale")
+
+	worker, err := workerCommand(2, incident)
+	suite.assert.Nil(err)
+	suite.assert.Contains(worker.Env, supervisedEnv+"=2")
+	suite.assert.NotContains(worker.Env, supervisorIncidentEnv+"=stale")
+
+	data, _ := json.Marshal(incident)
+	suite.assert.Contains(worker.Env, supervisorIncidentEnv+"="+string(data))
+	suite.assert.Equal(os.Args, worker.Args)
+}
+
+func (suite *supervisorTestSuite) TestProbeFileSystem() {
+	dir := suite.T().TempDir()
+	suite.assert.Nil(probeFileSystem(dir, ".blobfuse2"))
+	suite.assert.Nil(probeFileSystem(dir, ""))
+	suite.assert.NotNil(probeFileSystem(dir+"/missing", ""))
+}
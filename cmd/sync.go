@@ -0,0 +1,147 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+type syncOptions struct {
+	Output  string
+	Timeout time.Duration
+}
+
+var syncOpts syncOptions
+
+// Time given to reach the mount on top of the time it is given to upload
+const syncConnectGrace = 10 * time.Second
+
+// syncResult : Outcome of a sync as printed with --output=json
+type syncResult struct {
+	MountPath string              `json:"mountPath"`
+	Path      string              `json:"path,omitempty"`
+	Uploaded  int                 `json:"uploaded"`
+	Failures  []admin.SyncFailure `json:"failures"`
+}
+
+var syncCmd = &cobra.Command{
+	Use:               "sync <path>",
+	Short:             "Upload all modified files cached by a running mount and wait for it",
+	Long:              "Upload all files modified in the local cache of a running mount, or those under the given path, and wait till they are in storage. Files which could not be uploaded are reported with the reason and the command fails, use it before a node is scaled down or snapshotted.",
+	SuggestFor:        []string{"flush", "syncs"},
+	Example:           "blobfuse2 sync ~/mount_path\nblobfuse2 sync ~/mount_path/outputs --timeout=10m --output=json",
+	Args:              cobra.ExactArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if syncOpts.Output != "table" && syncOpts.Output != "json" {
+			return fmt.Errorf("invalid output format %s, shall be table or json", syncOpts.Output)
+		}
+
+		if syncOpts.Timeout < time.Second {
+			return fmt.Errorf("timeout shall be at least a second")
+		}
+
+		mountPath, name, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := admin.SendTimeout(mountPath, admin.Request{
+			Verb:       admin.VerbSync,
+			Path:       name,
+			TimeoutSec: int(syncOpts.Timeout.Seconds()),
+		}, syncOpts.Timeout+syncConnectGrace)
+		if resp.Sync == nil {
+			if err == nil {
+				err = fmt.Errorf("no report received")
+			}
+			return fmt.Errorf("failed to sync %s [%s]", args[0], err.Error())
+		}
+
+		result := syncResult{
+			MountPath: mountPath,
+			Path:      name,
+			Uploaded:  resp.Sync.Uploaded,
+			Failures:  resp.Sync.Failures,
+		}
+		if result.Failures == nil {
+			result.Failures = []admin.SyncFailure{}
+		}
+
+		if syncOpts.Output == "json" {
+			err = json.NewEncoder(cmd.OutOrStdout()).Encode(result)
+		} else {
+			err = printSync(cmd.OutOrStdout(), result)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(result.Failures) > 0 {
+			return fmt.Errorf("%d files of %s failed to upload", len(result.Failures), args[0])
+		}
+		return nil
+	},
+}
+
+// printSync : Files uploaded and the ones which failed along with the reason
+func printSync(out io.Writer, result syncResult) error {
+	fmt.Fprintf(out, "Uploaded %d files\n", result.Uploaded)
+	if len(result.Failures) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FAILED\tREASON")
+	for _, failure := range result.Failures {
+		fmt.Fprintf(w, "%s\t%s\n", failure.Path, failure.Reason)
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().StringVar(&syncOpts.Output, "output", "table", "Output format, table or json")
+	syncCmd.Flags().DurationVar(&syncOpts.Timeout, "timeout", 30*time.Minute,
+		"Time to wait for the uploads, files not uploaded by then are reported as failed")
+}
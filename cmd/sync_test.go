@@ -0,0 +1,141 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type syncCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *syncCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *syncCmdTestSuite) cleanupTest() {
+	syncOpts = syncOptions{}
+	listMountPoints = common.ListMountPoints
+	syncCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestSyncCommand(t *testing.T) {
+	suite.Run(t, new(syncCmdTestSuite))
+}
+
+func (suite *syncCmdTestSuite) TestSyncInvalidOutput() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "sync", suite.T().TempDir(), "--output=xml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid output format")
+}
+
+func (suite *syncCmdTestSuite) TestSyncInvalidTimeout() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "sync", suite.T().TempDir(), "--timeout=10ms")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "timeout shall be at least a second")
+}
+
+func (suite *syncCmdTestSuite) TestSync() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	// Mount is not serving requests yet
+	op, err := executeCommandC(rootCmd, "sync", mountPath)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "failed to sync")
+
+	var failures []admin.SyncFailure
+	var path string
+	var deadline time.Time
+	admin.RegisterSync("file_cache", func(req admin.Request, d time.Time) admin.SyncReport {
+		path, deadline = req.Path, d
+		return admin.SyncReport{Uploaded: 3, Failures: failures}
+	})
+	defer admin.UnregisterSync("file_cache")
+
+	err = admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	op, err = executeCommandC(rootCmd, "sync", filepath.Join(mountPath, "outputs"), "--timeout=2m")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "Uploaded 3 files")
+	suite.assert.Equal("outputs", path)
+	suite.assert.WithinDuration(time.Now().Add(2*time.Minute), deadline, 10*time.Second)
+
+	failures = []admin.SyncFailure{{Path: "outputs/part-1", Reason: "upload did not complete in time"}}
+	op, err = executeCommandC(rootCmd, "sync", mountPath, "--output=json")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "1 files of")
+
+	result := syncResult{}
+	err = json.NewDecoder(strings.NewReader(op)).Decode(&result)
+	suite.assert.Nil(err)
+	suite.assert.Equal(mountPath, result.MountPath)
+	suite.assert.Equal(3, result.Uploaded)
+	suite.assert.Equal(failures, result.Failures)
+
+	op, err = executeCommandC(rootCmd, "sync", mountPath, "--output=table")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "FAILED")
+	suite.assert.Contains(op, "outputs/part-1")
+}
@@ -0,0 +1,390 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/cobra"
+)
+
+type topOptions struct {
+	Output   string
+	Interval time.Duration
+	Count    uint32
+	Sort     string
+	Limit    uint32
+}
+
+var topOpts topOptions
+
+// Orders of the files in a report, busiest first except for path
+const (
+	topSortBytes   = "bytes"
+	topSortOps     = "ops"
+	topSortLatency = "latency"
+	topSortPath    = "path"
+)
+
+// Keys changing the order of the files while the report is shown on a terminal
+var topSortKeys = map[byte]string{
+	'b': topSortBytes,
+	'o': topSortOps,
+	'l': topSortLatency,
+	'p': topSortPath,
+}
+
+// Clears the terminal and moves the cursor to the top left corner
+const clearScreen = "\033[H\033[2J"
+
+// opActivity : Operations of one kind over one interval
+type opActivity struct {
+	Count        int64   `json:"count"`
+	Errors       int64   `json:"errors"`
+	MBps         float64 `json:"MBps"`
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+}
+
+// fileActivity : Operations on one file over one interval
+type fileActivity struct {
+	Path         string           `json:"path"`
+	Ops          map[string]int64 `json:"ops"` // operation -> count
+	Errors       int64            `json:"errors"`
+	ReadMBps     float64          `json:"readMBps"`
+	WriteMBps    float64          `json:"writeMBps"`
+	AvgLatencyMs float64          `json:"avgLatencyMs"`
+
+	bytes      int64
+	readBytes  int64
+	writeBytes int64
+	count      int64
+	latencyUs  int64
+}
+
+// topReport : Activity of a mount over one interval, derived from two reports of its per file counters
+type topReport struct {
+	MountPath   string                `json:"mountPath"`
+	Time        string                `json:"time"`
+	IntervalSec float64               `json:"intervalSec"`
+	Ops         map[string]opActivity `json:"ops"`
+	Files       []fileActivity        `json:"files"`
+}
+
+var topCmd = &cobra.Command{
+	Use:   "top <mount path>",
+	Short: "Show which files of a running mount are busiest",
+	Long: "Show which files of a running mount are busiest: operations per file and per kind of operation, throughput and latency, refreshed every interval like top does for processes. " +
+		"On a terminal the order can be changed with b (bytes), o (operations), l (latency) and p (path), q quits. " +
+		"The mount tracks activity only while someone watches it, so the first report starts after one interval.",
+	SuggestFor:        []string{"iotop", "activity"},
+	Example:           "blobfuse2 top ~/mount_path\nblobfuse2 top ~/mount_path --sort=latency --limit=10\nblobfuse2 top ~/mount_path --output=json --interval=5s --count=1",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeMountPaths,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topOpts.Output != "table" && topOpts.Output != "json" {
+			return fmt.Errorf("invalid output format %s, shall be table or json", topOpts.Output)
+		}
+
+		if topOpts.Sort != topSortBytes && topOpts.Sort != topSortOps && topOpts.Sort != topSortLatency && topOpts.Sort != topSortPath {
+			return fmt.Errorf("invalid sort order %s, shall be bytes, ops, latency or path", topOpts.Sort)
+		}
+
+		if topOpts.Interval <= 0 {
+			return fmt.Errorf("interval shall be greater than zero")
+		}
+
+		mountPath, _, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		// First request starts tracking on the mount
+		prev, err := fetchActivity(mountPath)
+		if err != nil {
+			return err
+		}
+		prevTime := time.Now()
+
+		out := cmd.OutOrStdout()
+		interactive := topOpts.Output == "table" && isTerminal(out) && isTerminal(os.Stdin)
+
+		var keys <-chan byte
+		if interactive {
+			var restore func()
+			keys, restore, err = readKeys(os.Stdin)
+			if err == nil {
+				defer restore()
+			}
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		ticker := time.NewTicker(topOpts.Interval)
+		defer ticker.Stop()
+
+		var last *topReport
+		for reports := uint32(0); topOpts.Count == 0 || reports < topOpts.Count; {
+			select {
+			case <-signals:
+				return nil
+
+			case key := <-keys:
+				if key == 'q' {
+					return nil
+				}
+				if order, found := topSortKeys[key]; found && last != nil {
+					topOpts.Sort = order
+					err = renderTop(out, *last, interactive)
+				}
+
+			case <-ticker.C:
+				cur, fetchErr := fetchActivity(mountPath)
+				if fetchErr != nil {
+					return fetchErr
+				}
+				now := time.Now()
+
+				report := deriveTop(mountPath, prev, cur, now.Sub(prevTime))
+				report.Time = now.Format(time.RFC3339)
+				last = &report
+				err = renderTop(out, report, interactive)
+				reports++
+
+				prev, prevTime = cur, now
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+// fetchActivity : Operations per file of the mount since it started tracking them
+func fetchActivity(mountPath string) ([]admin.FileActivity, error) {
+	resp, err := admin.Send(mountPath, admin.Request{Verb: admin.VerbTop})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity of %s [%s]", mountPath, err.Error())
+	}
+	return resp.Activity, nil
+}
+
+// deriveTop : Activity per operation and per file over the interval between two reports
+func deriveTop(mountPath string, prev []admin.FileActivity, cur []admin.FileActivity, elapsed time.Duration) topReport {
+	report := topReport{
+		MountPath:   mountPath,
+		IntervalSec: elapsed.Seconds(),
+		Ops:         make(map[string]opActivity),
+		Files:       make([]fileActivity, 0),
+	}
+
+	type prevKey struct{ path, op string }
+	before := make(map[prevKey]admin.FileActivity, len(prev))
+	for _, entry := range prev {
+		before[prevKey{entry.Path, entry.Op}] = entry
+	}
+
+	opBytes := make(map[string]int64)
+	opLatency := make(map[string]int64)
+	files := make(map[string]*fileActivity)
+	for _, entry := range cur {
+		// Counters going back mean tracking restarted on the mount, so they are all new
+		delta := entry
+		if old, found := before[prevKey{entry.Path, entry.Op}]; found && old.Count <= entry.Count {
+			delta.Count -= old.Count
+			delta.Errors -= old.Errors
+			delta.Bytes -= old.Bytes
+			delta.LatencyUs -= old.LatencyUs
+		}
+
+		if delta.Count == 0 {
+			continue
+		}
+
+		op := report.Ops[entry.Op]
+		op.Count += delta.Count
+		op.Errors += delta.Errors
+		report.Ops[entry.Op] = op
+		opBytes[entry.Op] += delta.Bytes
+		opLatency[entry.Op] += delta.LatencyUs
+
+		file, found := files[entry.Path]
+		if !found {
+			file = &fileActivity{Path: entry.Path, Ops: make(map[string]int64)}
+			files[entry.Path] = file
+		}
+		file.Ops[entry.Op] += delta.Count
+		file.Errors += delta.Errors
+		file.count += delta.Count
+		file.bytes += delta.Bytes
+		file.latencyUs += delta.LatencyUs
+		switch entry.Op {
+		case admin.OpRead:
+			file.readBytes += delta.Bytes
+		case admin.OpWrite:
+			file.writeBytes += delta.Bytes
+		}
+	}
+
+	toMBps := func(bytes int64) float64 {
+		if elapsed <= 0 {
+			return 0
+		}
+		return float64(bytes) / float64(common.MbToBytes) / elapsed.Seconds()
+	}
+
+	for name, op := range report.Ops {
+		op.MBps = toMBps(opBytes[name])
+		op.AvgLatencyMs = float64(opLatency[name]) / 1000 / float64(op.Count)
+		report.Ops[name] = op
+	}
+
+	for _, file := range files {
+		file.ReadMBps = toMBps(file.readBytes)
+		file.WriteMBps = toMBps(file.writeBytes)
+		file.AvgLatencyMs = float64(file.latencyUs) / 1000 / float64(file.count)
+		report.Files = append(report.Files, *file)
+	}
+
+	return report
+}
+
+// sortTop : Order the files of a report, busiest first
+func sortTop(files []fileActivity, order string) {
+	sort.SliceStable(files, func(i, j int) bool {
+		switch order {
+		case topSortBytes:
+			if files[i].bytes != files[j].bytes {
+				return files[i].bytes > files[j].bytes
+			}
+		case topSortOps:
+			if files[i].count != files[j].count {
+				return files[i].count > files[j].count
+			}
+		case topSortLatency:
+			if files[i].AvgLatencyMs != files[j].AvgLatencyMs {
+				return files[i].AvgLatencyMs > files[j].AvgLatencyMs
+			}
+		}
+		return files[i].Path < files[j].Path
+	})
+}
+
+// renderTop : Print the report in the configured order, replacing the previous one on a terminal
+func renderTop(out io.Writer, report topReport, interactive bool) error {
+	files := append([]fileActivity{}, report.Files...)
+	sortTop(files, topOpts.Sort)
+	if topOpts.Limit > 0 && uint32(len(files)) > topOpts.Limit {
+		files = files[:topOpts.Limit]
+	}
+	report.Files = files
+
+	if topOpts.Output == "json" {
+		return json.NewEncoder(out).Encode(report)
+	}
+
+	if interactive {
+		fmt.Fprint(out, clearScreen)
+	}
+	return printTop(out, report)
+}
+
+// printTop : Totals per operation followed by the busiest files
+func printTop(out io.Writer, report topReport) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Mount\t%s\n", report.MountPath)
+	fmt.Fprintf(w, "Time\t%s\n", report.Time)
+	fmt.Fprintf(w, "Sort\t%s\n", topOpts.Sort)
+
+	fmt.Fprintf(w, "\nOPERATION\tCOUNT\tERRORS\tMB/S\tAVG LATENCY MS\n")
+	for _, name := range sortedKeys(report.Ops) {
+		op := report.Ops[name]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%.3f\n", name, op.Count, op.Errors, op.MBps, op.AvgLatencyMs)
+	}
+
+	fmt.Fprintf(w, "\nOPENS\tREADS\tWRITES\tOTHER\tERRORS\tREAD MB/S\tWRITE MB/S\tAVG LATENCY MS\tFILE\n")
+	for _, file := range report.Files {
+		opens := file.Ops[admin.OpOpen] + file.Ops[admin.OpCreate]
+		reads := file.Ops[admin.OpRead]
+		writes := file.Ops[admin.OpWrite]
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%.2f\t%.2f\t%.3f\t%s\n",
+			opens, reads, writes, file.count-opens-reads-writes, file.Errors,
+			file.ReadMBps, file.WriteMBps, file.AvgLatencyMs, file.Path)
+	}
+	fmt.Fprintln(w)
+
+	return w.Flush()
+}
+
+// isTerminal : Whether the reader or writer is a terminal a person looks at
+func isTerminal(f any) bool {
+	file, ok := f.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+
+	topCmd.Flags().StringVar(&topOpts.Output, "output", "table",
+		"Output format, table or json")
+	topCmd.Flags().DurationVar(&topOpts.Interval, "interval", 2*time.Second,
+		"Time between reports, activity is measured over it")
+	topCmd.Flags().Uint32Var(&topOpts.Count, "count", 0,
+		"Number of reports to print, 0 keeps reporting until interrupted")
+	topCmd.Flags().StringVar(&topOpts.Sort, "sort", topSortBytes,
+		"Order of the files, bytes, ops, latency or path")
+	topCmd.Flags().Uint32Var(&topOpts.Limit, "limit", 20,
+		"Number of files to show, 0 shows all")
+}
@@ -0,0 +1,185 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type topCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *topCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *topCmdTestSuite) cleanupTest() {
+	topOpts = topOptions{}
+	listMountPoints = common.ListMountPoints
+	topCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestTopCommand(t *testing.T) {
+	suite.Run(t, new(topCmdTestSuite))
+}
+
+func (suite *topCmdTestSuite) TestTopInvalidOutput() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "top", suite.T().TempDir(), "--output=xml")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid output format")
+}
+
+func (suite *topCmdTestSuite) TestTopInvalidSort() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "top", suite.T().TempDir(), "--sort=size")
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "invalid sort order")
+}
+
+func (suite *topCmdTestSuite) TestDeriveTop() {
+	prev := []admin.FileActivity{
+		{Path: "a.txt", Op: admin.OpRead, Count: 10, Bytes: common.MbToBytes, LatencyUs: 1000},
+		{Path: "b.txt", Op: admin.OpOpen, Count: 1, LatencyUs: 500},
+		{Path: "c.txt", Op: admin.OpWrite, Count: 50, Bytes: 5 * common.MbToBytes, LatencyUs: 5000},
+	}
+	cur := []admin.FileActivity{
+		{Path: "a.txt", Op: admin.OpRead, Count: 14, Bytes: 5 * common.MbToBytes, LatencyUs: 9000},
+		{Path: "a.txt", Op: admin.OpOpen, Count: 2, Errors: 1, LatencyUs: 2000},
+		{Path: "b.txt", Op: admin.OpOpen, Count: 1, LatencyUs: 500},
+		{Path: "c.txt", Op: admin.OpWrite, Count: 2, Bytes: 2 * common.MbToBytes, LatencyUs: 6000},
+	}
+
+	report := deriveTop("/mnt", prev, cur, 2*time.Second)
+	suite.assert.Equal("/mnt", report.MountPath)
+
+	// b.txt saw no operation, c.txt counters went back so tracking restarted
+	suite.assert.Len(report.Files, 2)
+	sortTop(report.Files, topSortPath)
+	a, c := report.Files[0], report.Files[1]
+	suite.assert.Equal("a.txt", a.Path)
+	suite.assert.Equal(map[string]int64{admin.OpRead: 4, admin.OpOpen: 2}, a.Ops)
+	suite.assert.EqualValues(1, a.Errors)
+	suite.assert.InDelta(2, a.ReadMBps, 0.001)
+	suite.assert.InDelta(10.0/6, a.AvgLatencyMs, 0.001)
+	suite.assert.Equal("c.txt", c.Path)
+	suite.assert.InDelta(1, c.WriteMBps, 0.001)
+
+	suite.assert.EqualValues(4, report.Ops[admin.OpRead].Count)
+	suite.assert.InDelta(2, report.Ops[admin.OpRead].MBps, 0.001)
+	suite.assert.InDelta(2, report.Ops[admin.OpRead].AvgLatencyMs, 0.001)
+	suite.assert.EqualValues(1, report.Ops[admin.OpOpen].Errors)
+	suite.assert.NotContains(report.Ops, admin.OpFlush)
+
+	sortTop(report.Files, topSortBytes)
+	suite.assert.Equal("a.txt", report.Files[0].Path)
+	sortTop(report.Files, topSortOps)
+	suite.assert.Equal("a.txt", report.Files[0].Path)
+	sortTop(report.Files, topSortLatency)
+	suite.assert.Equal("c.txt", report.Files[0].Path)
+}
+
+func (suite *topCmdTestSuite) TestTop() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	// Mount is not serving requests yet
+	_, err := executeCommandC(rootCmd, "top", mountPath)
+	suite.assert.NotNil(err)
+
+	err = admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	// Applications keep reading while the mount is watched
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				admin.RecordActivity("a.txt", admin.OpRead, common.MbToBytes, time.Now(), nil)
+				admin.RecordActivity("b.txt", admin.OpOpen, 0, time.Now(), nil)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	op, err := executeCommandC(rootCmd, "top", mountPath, "--output=json", "--interval=20ms", "--count=2", "--limit=1")
+	suite.assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(op), "\n")
+	suite.assert.Len(lines, 2)
+	report := topReport{}
+	err = json.Unmarshal([]byte(lines[1]), &report)
+	suite.assert.Nil(err)
+	suite.assert.Equal(mountPath, report.MountPath)
+	suite.assert.Len(report.Files, 1)
+	suite.assert.Equal("a.txt", report.Files[0].Path)
+	suite.assert.Greater(report.Files[0].ReadMBps, float64(0))
+	suite.assert.Greater(report.Ops[admin.OpOpen].Count, int64(0))
+
+	op, err = executeCommandC(rootCmd, "top", mountPath, "--output=table", "--interval=20ms", "--count=1", "--sort=path", "--limit=0")
+	suite.assert.Nil(err)
+	suite.assert.Contains(op, "OPERATION")
+	suite.assert.Contains(op, "b.txt")
+	suite.assert.NotContains(op, clearScreen)
+}
@@ -35,24 +35,43 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
 
 	"github.com/Azure/azure-storage-fuse/v2/common"
 	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
 
 	"github.com/spf13/cobra"
 )
 
+type unmountOptions struct {
+	lazy          bool
+	force         bool
+	cleanupCache  bool
+	preserveCache bool
+}
+
+var unmountOpts unmountOptions
+
 var unmountCmd = &cobra.Command{
 	Use:               "unmount <mount path>",
 	Short:             "Unmount Blobfuse2",
 	Long:              "Unmount Blobfuse2",
 	SuggestFor:        []string{"unmount", "unmnt"},
-	Args:              cobra.ExactArgs(1),
+	Args:              usageArgs(cobra.ExactArgs(1)),
 	FlagErrorHandling: cobra.ExitOnError,
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		if unmountOpts.cleanupCache && unmountOpts.preserveCache {
+			return withExitCode(exitUsage, errors.New("--cleanup-cache and --preserve-cache can not be used together"))
+		}
+		return validateErrorOutput()
+	},
 	RunE: func(_ *cobra.Command, args []string) error {
 		if strings.Contains(args[0], "*") {
 			mntPathPrefix := args[0]
@@ -60,10 +79,11 @@ var unmountCmd = &cobra.Command{
 			lstMnt, _ := common.ListMountPoints()
 			for _, mntPath := range lstMnt {
 				match, _ := regexp.MatchString(mntPathPrefix, mntPath)
-				if match {
+				// Exports go along with the mount they were exposed from, which may have been unmounted already
+				if match && common.IsDirectoryMounted(mntPath) {
 					err := unmountBlobfuse2(mntPath)
 					if err != nil {
-						return fmt.Errorf("failed to unmount %s [%s]", mntPath, err.Error())
+						return keepExitCode(err, fmt.Errorf("failed to unmount %s [%s]", mntPath, err.Error()))
 					}
 				}
 			}
@@ -76,23 +96,61 @@ var unmountCmd = &cobra.Command{
 
 		return nil
 	},
-	ValidArgsFunction: func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		if toComplete == "" {
-			mntPts, _ := common.ListMountPoints()
-			return mntPts, cobra.ShellCompDirectiveNoFileComp
-		}
-		return nil, cobra.ShellCompDirectiveDefault
-	},
+	ValidArgsFunction: completeMountPaths,
 }
 
-// Attempts to unmount the directory and returns true if the operation succeeded
+// Attempts to unmount the directory along with the exports exposed from it and returns nil if the operation succeeded
 func unmountBlobfuse2(mntPath string) error {
+	// The mount decides on its cache when it stops, so it is told before it is unmounted
+	if unmountOpts.cleanupCache || unmountOpts.preserveCache {
+		disposition := admin.CachePreserve
+		if unmountOpts.cleanupCache {
+			disposition = admin.CacheCleanup
+		}
+
+		resp, err := admin.Send(mntPath, admin.Request{Verb: admin.VerbUnmount, Cache: disposition})
+		if err != nil && !strings.Contains(err.Error(), "not supported") {
+			return fmt.Errorf("failed to %s cache of %s [%s]", disposition, mntPath, err.Error())
+		}
+		if resp.Message != "" {
+			fmt.Println(resp.Message)
+		}
+	}
+
+	// Bind mounts keep the file system busy, so the exports go first
+	for _, export := range common.ListExports(mntPath) {
+		err := unmountPath(export)
+		if err != nil {
+			return keepExitCode(err, fmt.Errorf("failed to unmount export %s [%s]", export, err.Error()))
+		}
+	}
+
+	return unmountPath(mntPath)
+}
+
+// unmountPath : Unmount a single mount point through the fuse helper, or directly when there is none
+func unmountPath(mntPath string) error {
+	if unmountOpts.force {
+		// Fuse helpers can not force an unmount
+		err := unmountDirect(mntPath, unmountOpts.lazy, true)
+		if err != nil {
+			log.Err("unmountBlobfuse2 : failed to force unmount %s [%s]", mntPath, err.Error())
+			return withExitCode(unmountExitCode(mntPath, err), fmt.Errorf("failed to force unmount, which needs root [%s]", err.Error()))
+		}
+		fmt.Println("Successfully unmounted", mntPath)
+		return nil
+	}
+
 	unmountCmd := []string{"fusermount3", "fusermount"}
+	args := []string{"-u", mntPath}
+	if unmountOpts.lazy {
+		args = []string{"-u", "-z", mntPath}
+	}
 
 	var errb bytes.Buffer
 	var err error
 	for _, umntCmd := range unmountCmd {
-		cliOut := exec.Command(umntCmd, "-u", mntPath)
+		cliOut := exec.Command(umntCmd, args...)
 		cliOut.Stderr = &errb
 		_, err = cliOut.Output()
 
@@ -103,14 +161,49 @@ func unmountBlobfuse2(mntPath string) error {
 
 		if !strings.Contains(err.Error(), "executable file not found") {
 			log.Err("unmountBlobfuse2 : failed to unmount (%s : %s)", err.Error(), errb.String())
-			break
+			return withExitCode(unmountExitCode(mntPath, errors.New(errb.String())), fmt.Errorf("%s", errb.String()+" "+err.Error()))
 		}
 	}
 
-	return fmt.Errorf("%s", errb.String()+" "+err.Error())
+	// Rootless containers may ship no fuse helper, root of the user namespace which mounted directly can unmount too
+	uerr := unmountDirect(mntPath, unmountOpts.lazy, false)
+	if uerr == nil {
+		fmt.Println("Successfully unmounted", mntPath)
+		return nil
+	}
+	log.Err("unmountBlobfuse2 : no fuse helper found and failed to unmount %s [%s]", mntPath, uerr.Error())
+
+	return withExitCode(unmountExitCode(mntPath, uerr), fmt.Errorf("%s", errb.String()+" "+err.Error()))
+}
+
+// unmountExitCode : Exit code telling why the fuse helper or the kernel refused to unmount
+func unmountExitCode(mntPath string, err error) int {
+	if errors.Is(err, syscall.EBUSY) || strings.Contains(err.Error(), "busy") {
+		return exitBusy
+	}
+
+	if absPath, aerr := filepath.Abs(mntPath); aerr == nil && !common.IsDirectoryMounted(absPath) {
+		return exitNotMounted
+	}
+
+	return exitFailure
 }
 
 func init() {
 	rootCmd.AddCommand(unmountCmd)
 	unmountCmd.AddCommand(umntAllCmd)
+
+	unmountCmd.PersistentFlags().BoolVar(&unmountOpts.lazy, "lazy", false,
+		"Detach the mount now even if it is busy, it goes away once no longer in use.")
+	unmountCmd.PersistentFlags().BoolVar(&unmountOpts.force, "force", false,
+		"Abort the connection to blobfuse2 so requests in flight fail, needs root. Data not yet uploaded may be lost.")
+	unmountCmd.PersistentFlags().BoolVar(&unmountOpts.cleanupCache, "cleanup-cache", false,
+		"Remove the local file cache once unmounted even if it is persisted, files not yet uploaded are kept.")
+	unmountCmd.PersistentFlags().BoolVar(&unmountOpts.preserveCache, "preserve-cache", false,
+		"Keep the local file cache once unmounted even if it is not persisted.")
+	unmountCmd.Flags().StringVar(&errorOutput, "output", outputText,
+		"Format errors are printed in, text or json. With json a line like {\"exitCode\":9,\"cause\":\"not-mounted\",\"error\":\"...\"} is printed to stdout.")
+	_ = unmountCmd.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputText, outputJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
@@ -59,6 +59,11 @@ var umntAllCmd = &cobra.Command{
 		errMsg := "failed to unmount - \n"
 
 		for _, mntPath := range lstMnt {
+			if !common.IsDirectoryMounted(mntPath) {
+				// Export of a mount unmounted earlier in this loop
+				continue
+			}
+
 			mountfound += 1
 			err := unmountBlobfuse2(mntPath)
 			if err == nil {
@@ -81,6 +81,7 @@ func (suite *unmountTestSuite) SetupTest() {
 }
 
 func (suite *unmountTestSuite) cleanupTest() {
+	unmountOpts = unmountOptions{}
 	resetCLIFlags(*unmountCmd)
 	resetCLIFlags(*mountCmd)
 	resetCLIFlags(*rootCmd)
@@ -195,6 +196,29 @@ func (suite *unmountTestSuite) TestUnmountCmdValidArg() {
 	suite.assert.Empty(lst)
 }
 
+func (suite *unmountTestSuite) TestUnmountCmdLazyPreserveCache() {
+	defer suite.cleanupTest()
+
+	mountDirectory6, _ := os.MkdirTemp("", "TestUnMountTemp")
+	os.MkdirAll(mountDirectory6, 0777)
+	defer os.RemoveAll(mountDirectory6)
+
+	cmd := exec.Command("../blobfuse2", "mount", mountDirectory6, fmt.Sprintf("--config-file=%s", confFileUnMntTest))
+	_, err := cmd.Output()
+	suite.assert.Nil(err)
+
+	time.Sleep(5 * time.Second)
+
+	// Cache flags are mutually exclusive
+	_, err = executeCommandC(rootCmd, "unmount", mountDirectory6, "--cleanup-cache", "--preserve-cache")
+	suite.assert.NotNil(err)
+	unmountOpts = unmountOptions{}
+
+	// Loopback mount has no file cache, there is nothing to preserve
+	_, err = executeCommandC(rootCmd, "unmount", mountDirectory6, "--lazy", "--preserve-cache")
+	suite.assert.Nil(err)
+}
+
 func TestUnMountCommand(t *testing.T) {
 	confFile, err := os.CreateTemp("", "conf*.yaml")
 	if err != nil {
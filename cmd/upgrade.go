@@ -0,0 +1,233 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common/config"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/component/libfuse"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/sevlyar/go-daemon"
+	"github.com/spf13/cobra"
+)
+
+type upgradeOptions struct {
+	Binary  string
+	Timeout time.Duration
+}
+
+var upgradeOpts upgradeOptions
+
+// Time given to reach the mount on top of the time it is given to hand itself over
+const upgradeConnectGrace = 10 * time.Second
+
+// Time given to hand a mount over when the request does not tell
+const defaultUpgradeTimeout = 5 * time.Minute
+
+// Time the process which handed its mount over stays around, so the response to the request gets out
+const upgradeExitDelay = time.Second
+
+var upgradeCmd = &cobra.Command{
+	Use:               "upgrade <mount path>",
+	Short:             "Hand a running mount over to a new blobfuse2 process without unmounting",
+	Long:              "Start a new process of the given blobfuse2 binary, or the one installed at the path the mount runs, with the arguments the mount was started with and hand the connection to the kernel over to it. Applications keep their mount: operations are held back while the new process starts and are served by it afterwards. The mount is drained first, so it is handed over only once no file of it is open and no process works in it. Needs libfuse3 and Linux 6.9 or later, and is refused for mounts run by a supervisor or by the service.",
+	SuggestFor:        []string{"upgarde", "handover"},
+	Example:           "blobfuse2 upgrade ~/mount_path\nblobfuse2 upgrade ~/mount_path --binary=/opt/blobfuse2/bin/blobfuse2 --timeout=10m",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeMountPaths,
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if upgradeOpts.Timeout < time.Second {
+			return fmt.Errorf("timeout shall be at least a second")
+		}
+
+		binary := upgradeOpts.Binary
+		if binary != "" {
+			// The mount process resolves nothing relative to the working directory of this command
+			path, err := filepath.Abs(binary)
+			if err != nil {
+				return err
+			}
+			binary = path
+
+			err = checkExecutable(binary)
+			if err != nil {
+				return err
+			}
+		}
+
+		mountPath, _, err := resolveMountPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := admin.SendTimeout(mountPath, admin.Request{
+			Verb:       admin.VerbUpgrade,
+			Binary:     binary,
+			TimeoutSec: int(upgradeOpts.Timeout.Seconds()),
+		}, upgradeOpts.Timeout+upgradeConnectGrace)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade %s [%s]", mountPath, err.Error())
+		}
+
+		if resp.Message != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), resp.Message)
+		}
+		return nil
+	},
+}
+
+// checkExecutable : Fail unless the path is a file which can be run
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("%s is not an executable", path)
+	}
+	return nil
+}
+
+// upgradeMount : Hand the mount over to a new process and exit once it serves the mount. Nothing is unmounted or
+// cleaned up, the new process goes on with the same cache.
+func upgradeMount(req admin.Request) (string, error) {
+	// Either would mount again once this process is gone
+	if _, supervised := supervisedRestarts(); supervised {
+		return "", errors.New("mount is run by a supervisor, restart the supervisor instead")
+	}
+	if name := os.Getenv(serviceMountEnv); name != "" {
+		return "", fmt.Errorf("mount is run by the service as %s, restart it through the service instead", name)
+	}
+
+	worker, err := upgradeCommand(req.Binary)
+	if err != nil {
+		return "", err
+	}
+
+	timeout := time.Duration(req.TimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = defaultUpgradeTimeout
+	}
+
+	mountPath, _ := filepath.Abs(options.MountPath)
+	pid, err := libfuse.HandOver(libfuse.Handover{
+		Worker:   worker,
+		Deadline: time.Now().Add(timeout),
+		Release: func() {
+			admin.Detach()
+			admin.StopHTTP()
+		},
+		Restore: func() {
+			startAdmin(mountPath)
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	go exitHandedOver(pid)
+	return fmt.Sprintf("%s is served by process %d from now on", options.MountPath, pid), nil
+}
+
+// upgradeCommand : Mount command as this mount was started with, run by the given binary or else the one installed
+// at the path of the running one
+func upgradeCommand(binary string) (*exec.Cmd, error) {
+	if binary == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+		binary = exe
+	}
+
+	err := checkExecutable(binary)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if name != daemon.MARK_NAME {
+			env = append(env, kv)
+		}
+	}
+
+	// Arguments are passed on as they came, so the new process reads the same config the same way
+	return &exec.Cmd{
+		Path:   binary,
+		Args:   os.Args,
+		Env:    env,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}, nil
+}
+
+// prepareTakeover : Settings of a process started to take over a mount. The process handing over runs in background
+// already if asked to, and leaves its cache behind for this one.
+func prepareTakeover() {
+	options.Foreground = true
+	config.Set("file_cache.allow-non-empty-temp", "true")
+	config.Set("file_cache.cleanup-on-start", "false")
+	if config.IsSet("file_cache.empty-dir-check") {
+		config.Set("file_cache.empty-dir-check", "false")
+	}
+}
+
+// exitHandedOver : Leave the mount to the process which took it over
+func exitHandedOver(pid int) {
+	time.Sleep(upgradeExitDelay)
+	log.Crit("Mount::exitHandedOver : %s is served by process %d, exiting", options.MountPath, pid)
+	_ = log.Destroy()
+	os.Exit(0)
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	upgradeCmd.Flags().StringVar(&upgradeOpts.Binary, "binary", "",
+		"blobfuse2 binary to run the mount from now on, by default the one installed at the path the mount runs")
+	upgradeCmd.Flags().DurationVar(&upgradeOpts.Timeout, "timeout", defaultUpgradeTimeout,
+		"Time to wait for the mount to drain and the new process to take over, the mount stays as it is otherwise")
+}
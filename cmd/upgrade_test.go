@@ -0,0 +1,140 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+	"github.com/Azure/azure-storage-fuse/v2/internal/admin"
+
+	"github.com/sevlyar/go-daemon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type upgradeCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *upgradeCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *upgradeCmdTestSuite) cleanupTest() {
+	listMountPoints = common.ListMountPoints
+	upgradeOpts = upgradeOptions{Timeout: defaultUpgradeTimeout}
+}
+
+func TestUpgradeCommand(t *testing.T) {
+	suite.Run(t, new(upgradeCmdTestSuite))
+}
+
+func (suite *upgradeCmdTestSuite) TestUpgradeNotMounted() {
+	defer suite.cleanupTest()
+	listMountPoints = func() ([]string, error) { return []string{}, nil }
+
+	op, err := executeCommandC(rootCmd, "upgrade", suite.T().TempDir())
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "is not inside a blobfuse2 mount")
+}
+
+func (suite *upgradeCmdTestSuite) TestUpgradeInvalidBinary() {
+	defer suite.cleanupTest()
+	binary := filepath.Join(suite.T().TempDir(), "blobfuse2")
+	err := os.WriteFile(binary, []byte("not a program"), 0644)
+	suite.assert.Nil(err)
+
+	op, err := executeCommandC(rootCmd, "upgrade", suite.T().TempDir(), "--binary="+binary)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "is not an executable")
+}
+
+func (suite *upgradeCmdTestSuite) TestUpgradeRefused() {
+	defer suite.cleanupTest()
+	workDir := common.DefaultWorkDir
+	common.DefaultWorkDir = suite.T().TempDir()
+	defer func() { common.DefaultWorkDir = workDir }()
+
+	mountPath := suite.T().TempDir()
+	listMountPoints = func() ([]string, error) { return []string{mountPath}, nil }
+
+	admin.RegisterHandler(admin.VerbUpgrade, "mount", upgradeMount)
+	defer admin.UnregisterHandler(admin.VerbUpgrade, "mount")
+
+	err := admin.Start(mountPath)
+	suite.assert.Nil(err)
+	defer admin.Stop()
+
+	// A supervisor or the service would mount again once the mount process is gone
+	suite.T().Setenv(supervisedEnv, "0")
+	op, err := executeCommandC(rootCmd, "upgrade", mountPath)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "run by a supervisor")
+
+	os.Unsetenv(supervisedEnv)
+	suite.T().Setenv(serviceMountEnv, "data")
+	op, err = executeCommandC(rootCmd, "upgrade", mountPath)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "run by the service as data")
+
+	// The pipeline of this process serves nothing
+	os.Unsetenv(serviceMountEnv)
+	op, err = executeCommandC(rootCmd, "upgrade", mountPath)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "mount is not served by this process")
+}
+
+func (suite *upgradeCmdTestSuite) TestUpgradeCommand() {
+	defer suite.cleanupTest()
+	suite.T().Setenv(daemon.MARK_NAME, daemon.MARK_VALUE)
+
+	worker, err := upgradeCommand("")
+	suite.assert.Nil(err)
+	exe, _ := os.Executable()
+	suite.assert.Equal(exe, worker.Path)
+	suite.assert.Equal(os.Args, worker.Args)
+	suite.assert.NotContains(worker.Env, daemon.MARK_NAME+"="+daemon.MARK_VALUE)
+
+	_, err = upgradeCommand(filepath.Join(suite.T().TempDir(), "blobfuse2"))
+	suite.assert.NotNil(err)
+}
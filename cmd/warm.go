@@ -0,0 +1,157 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/component/file_cache"
+
+	"github.com/spf13/cobra"
+)
+
+type warmOptions struct {
+	Manifest    string
+	Concurrency int
+}
+
+var warmOpts warmOptions
+
+var warmCmd = &cobra.Command{
+	Use:               "warm [mount path]",
+	Short:             "Download the files listed in a manifest into the local cache of a mount",
+	Long:              "Download the files listed in a manifest into the local cache of a mount. The manifest holds one path per line relative to the root of the mount, blank lines and lines starting with # are skipped. Mount path can be left out when there is only one blobfuse2 mount.",
+	SuggestFor:        []string{"wrm", "warn"},
+	Example:           "blobfuse2 warm ~/mount_path --manifest=list.txt --concurrency=16",
+	Args:              cobra.MaximumNArgs(1),
+	FlagErrorHandling: cobra.ExitOnError,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if warmOpts.Manifest == "" {
+			return errors.New("manifest not provided, check usage")
+		}
+
+		mountPath, err := warmMountPath(args)
+		if err != nil {
+			return err
+		}
+
+		names, err := file_cache.ReadManifest(common.ExpandPath(warmOpts.Manifest))
+		if err != nil {
+			return fmt.Errorf("failed to read manifest %s [%s]", warmOpts.Manifest, err.Error())
+		}
+
+		lastReport := time.Now()
+		stats := file_cache.Warm(names, warmOpts.Concurrency, func(name string) (int64, error) {
+			return warmFile(filepath.Join(mountPath, name))
+		}, func(name string, err error, stats file_cache.WarmStats) {
+			if err != nil {
+				fmt.Printf("failed to warm %s [%s]\n", name, err.Error())
+			}
+
+			if time.Since(lastReport) >= file_cache.WarmReportInterval {
+				lastReport = time.Now()
+				fmt.Printf("%d of %d files done, %d bytes, %d failed\n", stats.Done, stats.Total, stats.Bytes, stats.Failed)
+			}
+		}, nil)
+
+		fmt.Printf("warmed %d of %d files, %d bytes, %d failed\n", stats.Done-stats.Failed, stats.Total, stats.Bytes, stats.Failed)
+		if stats.Failed > 0 {
+			return fmt.Errorf("failed to warm %d of %d files", stats.Failed, stats.Total)
+		}
+
+		return nil
+	},
+}
+
+// warmMountPath : Mount path given on command line, or the only blobfuse2 mount when none is given
+func warmMountPath(args []string) (string, error) {
+	mountPath := ""
+	if len(args) == 1 {
+		mountPath = common.ExpandPath(args[0])
+	} else {
+		mounts, err := common.ListMountPoints()
+		if err != nil {
+			return "", fmt.Errorf("failed to list mount points [%s]", err.Error())
+		}
+		if len(mounts) != 1 {
+			return "", fmt.Errorf("mount path not provided and %d mounts found, check usage", len(mounts))
+		}
+		mountPath = mounts[0]
+	}
+
+	info, err := os.Stat(mountPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to access mount path %s [%s]", mountPath, err.Error())
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("mount path %s is not a directory", mountPath)
+	}
+
+	return mountPath, nil
+}
+
+// warmFile : Open and close a file through the mount, file_cache downloads a file when it is opened
+func warmFile(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.IsDir() {
+		return 0, fmt.Errorf("%s is a directory", path)
+	}
+
+	return info.Size(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(warmCmd)
+
+	warmCmd.Flags().StringVar(&warmOpts.Manifest, "manifest", "",
+		"File listing the paths to warm, one per line relative to the root of the mount")
+	_ = warmCmd.MarkFlagFilename("manifest")
+
+	warmCmd.Flags().IntVar(&warmOpts.Concurrency, "concurrency", 8,
+		"Number of files warmed at a time")
+}
@@ -0,0 +1,108 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+	"github.com/Azure/azure-storage-fuse/v2/common/log"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type warmCmdTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *warmCmdTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	err := log.SetDefaultLogger("silent", common.LogConfig{Level: common.ELogLevel.LOG_DEBUG()})
+	if err != nil {
+		panic("Unable to set silent logger as default.")
+	}
+}
+
+func (suite *warmCmdTestSuite) cleanupTest() {
+	warmOpts = warmOptions{}
+	warmCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestWarmCommand(t *testing.T) {
+	suite.Run(t, new(warmCmdTestSuite))
+}
+
+func (suite *warmCmdTestSuite) TestWarmNoManifest() {
+	defer suite.cleanupTest()
+	op, err := executeCommandC(rootCmd, "warm", suite.T().TempDir())
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "manifest not provided")
+}
+
+func (suite *warmCmdTestSuite) TestWarmInvalidMountPath() {
+	defer suite.cleanupTest()
+	manifest := filepath.Join(suite.T().TempDir(), "list.txt")
+	suite.assert.Nil(os.WriteFile(manifest, []byte("a.txt\n"), 0644))
+
+	op, err := executeCommandC(rootCmd, "warm", "/mntNotFound", "--manifest="+manifest)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "failed to access mount path")
+}
+
+func (suite *warmCmdTestSuite) TestWarm() {
+	defer suite.cleanupTest()
+	mountPath := suite.T().TempDir()
+	suite.assert.Nil(os.MkdirAll(filepath.Join(mountPath, "dir"), 0755))
+	suite.assert.Nil(os.WriteFile(filepath.Join(mountPath, "a.txt"), []byte("abc"), 0644))
+	suite.assert.Nil(os.WriteFile(filepath.Join(mountPath, "dir", "b.txt"), []byte("defg"), 0644))
+
+	manifest := filepath.Join(suite.T().TempDir(), "list.txt")
+	suite.assert.Nil(os.WriteFile(manifest, []byte("# inputs\na.txt\n\n/dir/b.txt\n"), 0644))
+
+	_, err := executeCommandC(rootCmd, "warm", mountPath, "--manifest="+manifest, "--concurrency=2")
+	suite.assert.Nil(err)
+
+	suite.assert.Nil(os.WriteFile(manifest, []byte("a.txt\nmissing.txt\ndir\n"), 0644))
+	op, err := executeCommandC(rootCmd, "warm", mountPath, "--manifest="+manifest)
+	suite.assert.NotNil(err)
+	suite.assert.Contains(op, "failed to warm 2 of 3 files")
+}
@@ -34,6 +34,7 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -82,6 +83,12 @@ type options struct {
 	completionFuncMap map[string]func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
 	secureConfig      bool
 	passphrase        string
+
+	// Values of the config which held ${ENV_VAR} or @file:/path references
+	references map[string]reference
+
+	// Flags named after config keys which were bound to a flag already, by that flag
+	aliases map[*pflag.Flag][]*pflag.Flag
 }
 
 var userOptions options
@@ -108,16 +115,30 @@ func ReadFromConfigFile(configFilePath string) error {
 		return err
 	}
 
+	err = resolveFileReferences()
+	if err != nil {
+		return err
+	}
+
 	WatchConfig()
 	return nil
 }
 
+// resolveFileReferences : Resolve the references in the config file viper read last
+func resolveFileReferences() error {
+	data, err := os.ReadFile(userOptions.path)
+	if err != nil {
+		return err
+	}
+	return resolveReferences(data)
+}
+
 func loadConfigFromBufferToViper(configData []byte) error {
 	err := viper.ReadConfig(strings.NewReader(string(configData)))
 	if err != nil {
 		return err
 	}
-	return nil
+	return resolveReferences(configData)
 }
 
 // ReadFromConfigBuffer is used to the configFilePath and initialize viper object
@@ -164,18 +185,58 @@ func WatchConfig() {
 				log.Err("WatchConfig : %s", err.Error())
 				return
 			}
+		} else {
+			err := resolveFileReferences()
+			if err != nil {
+				log.Err("WatchConfig : %s", err.Error())
+				return
+			}
 		}
 		OnConfigChange()
 	})
 }
 
+// Reload reads the config file again and notifies the listeners, for changes to be applied on request
+// instead of waiting for the file watch
+func Reload() error {
+	if userOptions.path == "" {
+		return fmt.Errorf("config was not read from a file")
+	}
+
+	if userOptions.secureConfig {
+		err := DecryptConfigFile(userOptions.path, userOptions.passphrase)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := viper.ReadInConfig()
+		if err != nil {
+			return fmt.Errorf("failed to read config file [%s]", err.Error())
+		}
+
+		err = resolveFileReferences()
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Crit("Reload : Config reloaded from %s", userOptions.path)
+	OnConfigChange()
+	return nil
+}
+
 func ReadConfigFromReader(reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
 	viper.SetConfigType("yaml")
-	err := viper.ReadConfig(reader)
+	err = viper.ReadConfig(bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
-	return nil
+	return resolveReferences(data)
 }
 
 // AddConfigChangeEventListener function is used to register any ConfigChangeEventHandler
@@ -226,7 +287,18 @@ func BindPFlag(key string, flag *pflag.Flag) {
 //
 // the key parameter should take on the value "auth.key"
 func UnmarshalKey(key string, obj interface{}) error {
-	err := viper.UnmarshalKey(key, obj, func(decodeConfig *mapstructure.DecoderConfig) { decodeConfig.TagName = STRUCT_TAG })
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           obj,
+		TagName:          STRUCT_TAG,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err == nil {
+		err = decoder.Decode(settingsAt(key))
+	}
 	if err != nil {
 		return fmt.Errorf("config error: unmarshalling [%v]", err)
 	}
@@ -241,7 +313,9 @@ func UnmarshalKey(key string, obj interface{}) error {
 	})
 	userOptions.flagTree.MergeWithKey(key, obj, func(val interface{}) (interface{}, bool) {
 		flag := val.(*pflag.Flag)
-		if flag.Changed {
+		if sv, ok := flag.Value.(pflag.SliceValue); ok && flagChanged(flag) {
+			return sv.GetSlice(), true
+		} else if flagChanged(flag) {
 			return flag.Value.String(), true
 		} else {
 			return "", false
@@ -250,6 +324,25 @@ func UnmarshalKey(key string, obj interface{}) error {
 	return nil
 }
 
+// settingsAt returns the value of the key with defaults merged in below it.
+// viper.Get hands out the map of the first source holding the key, which hides the defaults of the keys under it.
+func settingsAt(key string) interface{} {
+	value := viper.Get(key)
+	if _, ok := value.(map[string]interface{}); !ok {
+		return value
+	}
+
+	var node interface{} = viper.AllSettings()
+	for _, part := range strings.Split(strings.ToLower(key), ".") {
+		section, ok := node.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		node = section[part]
+	}
+	return node
+}
+
 // Unmarshal populates the passed object and all the exported fields.
 // use lower case attribute names to ignore a particular field
 func Unmarshal(obj interface{}) error {
@@ -268,7 +361,9 @@ func Unmarshal(obj interface{}) error {
 	})
 	userOptions.flagTree.Merge(obj, func(val interface{}) (interface{}, bool) {
 		flag := val.(*pflag.Flag)
-		if flag.Changed {
+		if sv, ok := flag.Value.(pflag.SliceValue); ok && flagChanged(flag) {
+			return sv.GetSlice(), true
+		} else if flagChanged(flag) {
 			return flag.Value.String(), true
 		} else {
 			return "", false
@@ -286,19 +381,27 @@ func SetBool(key string, val bool) {
 	viper.Set(key, val)
 }
 
+// SetDefault gives the key a value that the config file, environment and flags all take precedence over
+func SetDefault(key string, val interface{}) {
+	viper.SetDefault(key, val)
+}
+
 func IsSet(key string) bool {
 	if viper.IsSet(key) {
 		return true
 	}
-	pieces := strings.Split(key, ".")
-	node := userOptions.flagTree.head
-	for _, s := range pieces {
-		node = node.children[s]
-		if node == nil {
-			return false
+	if node := userOptions.envTree.GetSubTree(key); node != nil && node.value != nil {
+		if _, ok := os.LookupEnv(node.value.(string)); ok {
+			return true
 		}
 	}
-	return node.value.(*pflag.Flag).Changed
+
+	node := userOptions.flagTree.GetSubTree(key)
+	if node == nil {
+		return false
+	}
+	flag, ok := node.value.(*pflag.Flag)
+	return ok && flagChanged(flag)
 }
 
 // AttachToFlagSet is used to attach the flags in config to the cmd flags
@@ -384,6 +487,11 @@ func AddUint64Flag(name string, value uint64, usage string) *pflag.Flag {
 	return userOptions.flags.Lookup(name)
 }
 
+func AddStringSliceFlag(name string, value []string, usage string) *pflag.Flag {
+	userOptions.flags.StringSlice(name, value, usage)
+	return userOptions.flags.Lookup(name)
+}
+
 func AddDurationFlag(name string, value time.Duration, usage string) *pflag.Flag {
 	userOptions.flags.Duration(name, value, usage)
 	return userOptions.flags.Lookup(name)
@@ -401,6 +509,7 @@ func ResetConfig() {
 		flags:     pflag.NewFlagSet("config-options", pflag.ContinueOnError),
 		flagTree:  NewTree(),
 		envTree:   NewTree(),
+		aliases:   make(map[*pflag.Flag][]*pflag.Flag),
 	}
 }
 
@@ -409,5 +518,6 @@ func init() {
 
 	userOptions.flagTree = NewTree()
 	userOptions.envTree = NewTree()
+	userOptions.aliases = make(map[*pflag.Flag][]*pflag.Flag)
 	userOptions.completionFuncMap = make(map[string]func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective))
 }
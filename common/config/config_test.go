@@ -483,6 +483,61 @@ func (suite *ConfigTestSuite) TestConfigFileDescryption() {
 	_ = os.Remove("test_enc.yaml")
 }
 
+func (suite *ConfigTestSuite) TestReload() {
+	defer suite.cleanupTest()
+	assert := assert.New(suite.T())
+
+	err := Reload()
+	assert.NotNil(err)
+
+	err = os.WriteFile("test_reload.yaml", []byte("logging:\n  level: log_warning\n"), 0644)
+	assert.Nil(err)
+	defer os.Remove("test_reload.yaml")
+
+	err = ReadFromConfigFile("test_reload.yaml")
+	assert.Nil(err)
+
+	changes := 0
+	AddConfigChangeEventListener(ConfigChangeEventHandlerFunc(func() { changes++ }))
+
+	err = os.WriteFile("test_reload.yaml", []byte("logging:\n  level: log_debug\n"), 0644)
+	assert.Nil(err)
+
+	err = Reload()
+	assert.Nil(err)
+	assert.GreaterOrEqual(changes, 1)
+
+	level := ""
+	err = UnmarshalKey("logging.level", &level)
+	assert.Nil(err)
+	assert.Equal("log_debug", level)
+}
+
+func (suite *ConfigTestSuite) TestSetDefault() {
+	defer suite.cleanupTest()
+	assert := assert.New(suite.T())
+
+	err := os.WriteFile("test_default.yaml", []byte("file_cache:\n  timeout-sec: 30\n"), 0644)
+	assert.Nil(err)
+	defer os.Remove("test_default.yaml")
+
+	err = ReadFromConfigFile("test_default.yaml")
+	assert.Nil(err)
+
+	SetDefault("file_cache.timeout-sec", 120)
+	SetDefault("file_cache.policy", "lfu")
+	assert.True(IsSet("file_cache.policy"))
+
+	opt := struct {
+		Timeout uint32 `config:"timeout-sec"`
+		Policy  string `config:"policy"`
+	}{}
+	err = UnmarshalKey("file_cache", &opt)
+	assert.Nil(err)
+	assert.EqualValues(30, opt.Timeout)
+	assert.Equal("lfu", opt.Policy)
+}
+
 func (suite *ConfigTestSuite) cleanupTest() {
 	ResetConfig()
 }
@@ -0,0 +1,262 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Azure/azure-storage-fuse/v2/common"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// A value of the form @file:/path is replaced with the content of the file, e.g. a mounted secret
+const filePrefix = "@file:"
+
+// resolveReferences : Replace ${ENV_VAR} and @file:/path in the values of the config viper just read, data being its raw content.
+// Only the values holding references are merged in to viper, the rest stay as viper read them.
+func resolveReferences(data []byte) error {
+	conf := make(map[string]interface{})
+	err := yaml.Unmarshal(data, &conf)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := interpolateMap(conf)
+	if err != nil {
+		return err
+	}
+
+	userOptions.references = make(map[string]reference)
+	recordReferences("", conf, resolved)
+
+	if len(resolved) == 0 {
+		return nil
+	}
+	return viper.MergeConfigMap(resolved)
+}
+
+// reference : Value of a key as written in the config and what it resolved to
+type reference struct {
+	raw      interface{}
+	resolved interface{}
+}
+
+// recordReferences : Remember the values which held references, by their dot separated key
+func recordReferences(prefix string, conf map[string]interface{}, resolved map[string]interface{}) {
+	for k, v := range resolved {
+		key := strings.ToLower(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		sub, isMap := v.(map[string]interface{})
+		confSub, confIsMap := conf[k].(map[string]interface{})
+		if isMap && confIsMap {
+			recordReferences(key, confSub, sub)
+			continue
+		}
+		userOptions.references[key] = reference{raw: conf[k], resolved: v}
+	}
+}
+
+// RestoreReferences : Put the ${ENV_VAR} and @file:/path references back in settings about to be written to a file,
+// so secrets kept out of the config are not written out. Keys changed since the config was loaded keep their value.
+func RestoreReferences(settings map[string]interface{}) {
+	for key, ref := range userOptions.references {
+		parts := strings.Split(key, ".")
+		cur := settings
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := cur[part].(map[string]interface{})
+			if !ok {
+				cur = nil
+				break
+			}
+			cur = next
+		}
+
+		last := parts[len(parts)-1]
+		if cur != nil && reflect.DeepEqual(cur[last], ref.resolved) {
+			cur[last] = ref.raw
+		}
+	}
+}
+
+// ResolveReferences : Parsed config with ${ENV_VAR} and @file:/path in its values resolved, as they are when the config is loaded
+func ResolveReferences(conf map[string]interface{}) (map[string]interface{}, error) {
+	resolved, err := interpolateMap(conf)
+	if err != nil {
+		return nil, err
+	}
+	return mergeResolved(conf, resolved), nil
+}
+
+// interpolateMap : Top level keys of conf whose values changed once resolved
+func interpolateMap(conf map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{})
+	for key, val := range conf {
+		res, changed, err := interpolateValue(key, val)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			resolved[key] = res
+		}
+	}
+	return resolved, nil
+}
+
+// interpolateValue : Resolve the references in val, maps come back with only the keys which changed
+func interpolateValue(key string, val interface{}) (interface{}, bool, error) {
+	switch v := val.(type) {
+	case string:
+		return interpolateString(key, v)
+
+	case map[string]interface{}:
+		resolved := make(map[string]interface{})
+		for k, item := range v {
+			res, changed, err := interpolateValue(key+"."+k, item)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				resolved[k] = res
+			}
+		}
+		return resolved, len(resolved) > 0, nil
+
+	case []interface{}:
+		// Lists are replaced as a whole so items keep their place
+		list := make([]interface{}, len(v))
+		anyChanged := false
+		for i, item := range v {
+			res, changed, err := interpolateValue(fmt.Sprintf("%s[%d]", key, i), item)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if _, isMap := item.(map[string]interface{}); isMap {
+					res = mergeResolved(item.(map[string]interface{}), res.(map[string]interface{}))
+				}
+				list[i] = res
+				anyChanged = true
+			} else {
+				list[i] = item
+			}
+		}
+		return list, anyChanged, nil
+	}
+
+	return val, false, nil
+}
+
+// mergeResolved : Full map with the resolved values laid over the original ones
+func mergeResolved(orig map[string]interface{}, resolved map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(orig))
+	for k, v := range orig {
+		out[k] = v
+	}
+	for k, v := range resolved {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if origSub, ok := orig[k].(map[string]interface{}); ok {
+				v = mergeResolved(origSub, sub)
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// interpolateString : Expand ${ENV_VAR} and ${ENV_VAR:-default}, then read the file if the value is @file:/path.
+// $${...} is kept as a literal ${...}.
+func interpolateString(key string, val string) (string, bool, error) {
+	if !strings.Contains(val, "${") && !strings.HasPrefix(val, filePrefix) {
+		return val, false, nil
+	}
+
+	var sb strings.Builder
+	rest := val
+	for {
+		idx := strings.Index(rest, "${")
+		if idx < 0 {
+			sb.WriteString(rest)
+			break
+		}
+
+		if idx > 0 && rest[idx-1] == '$' {
+			sb.WriteString(rest[:idx-1])
+			sb.WriteString("${")
+			rest = rest[idx+2:]
+			continue
+		}
+
+		end := strings.Index(rest[idx:], "}")
+		if end < 0 {
+			return "", false, fmt.Errorf("unterminated ${ in value of %s", key)
+		}
+
+		sb.WriteString(rest[:idx])
+		name := rest[idx+2 : idx+end]
+		def, hasDefault := "", false
+		if i := strings.Index(name, ":-"); i >= 0 {
+			name, def, hasDefault = name[:i], name[i+2:], true
+		}
+
+		envVal, ok := os.LookupEnv(name)
+		if !ok || (envVal == "" && hasDefault) {
+			if !hasDefault {
+				return "", false, fmt.Errorf("environment variable %s referenced by %s is not set", name, key)
+			}
+			envVal = def
+		}
+		sb.WriteString(envVal)
+		rest = rest[idx+end+1:]
+	}
+
+	res := sb.String()
+	if strings.HasPrefix(res, filePrefix) {
+		path := common.ExpandPath(strings.TrimPrefix(res, filePrefix))
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s referenced by %s [%s]", path, key, err.Error())
+		}
+		res = strings.TrimRight(string(content), "\r\n")
+	}
+
+	return res, true, nil
+}
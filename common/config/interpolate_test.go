@@ -0,0 +1,171 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type interpolateTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func (suite *interpolateTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	ResetConfig()
+}
+
+func (suite *interpolateTestSuite) TearDownTest() {
+	ResetConfig()
+}
+
+func TestInterpolate(t *testing.T) {
+	suite.Run(t, new(interpolateTestSuite))
+}
+
+type interpolateOptions struct {
+	Account   string   `config:"account-name"`
+	Key       string   `config:"account-key"`
+	Container string   `config:"container"`
+	Timeout   uint32   `config:"timeout-sec"`
+	Tier      string   `config:"tier"`
+	Literal   string   `config:"literal"`
+	Pin       []string `config:"pin"`
+}
+
+func (suite *interpolateTestSuite) TestEnvAndFile() {
+	keyFile := filepath.Join(suite.T().TempDir(), "key")
+	suite.assert.Nil(os.WriteFile(keyFile, []byte("secret-key\n"), 0600))
+
+	suite.T().Setenv("BF2_TEST_ACCOUNT", "myaccount")
+	suite.T().Setenv("BF2_TEST_TIMEOUT", "300")
+	suite.T().Setenv("BF2_TEST_KEY_FILE", keyFile)
+
+	err := ReadConfigFromReader(strings.NewReader(`
+azstorage:
+  account-name: ${BF2_TEST_ACCOUNT}
+  account-key: "@file:${BF2_TEST_KEY_FILE}"
+  container: logs-${BF2_TEST_ACCOUNT}-${BF2_TEST_UNSET:-default}
+  timeout-sec: ${BF2_TEST_TIMEOUT}
+  tier: hot
+  literal: $${BF2_TEST_ACCOUNT}
+  pin:
+    - data/${BF2_TEST_ACCOUNT}/**
+    - static/**
+`))
+	suite.assert.Nil(err)
+
+	opts := interpolateOptions{}
+	suite.assert.Nil(UnmarshalKey("azstorage", &opts))
+	suite.assert.Equal("myaccount", opts.Account)
+	suite.assert.Equal("secret-key", opts.Key)
+	suite.assert.Equal("logs-myaccount-default", opts.Container)
+	suite.assert.EqualValues(300, opts.Timeout)
+	suite.assert.Equal("hot", opts.Tier)
+	suite.assert.Equal("${BF2_TEST_ACCOUNT}", opts.Literal)
+	suite.assert.Equal([]string{"data/myaccount/**", "static/**"}, opts.Pin)
+}
+
+func (suite *interpolateTestSuite) TestUnsetVariable() {
+	err := ReadConfigFromReader(strings.NewReader("azstorage:\n  account-name: ${BF2_TEST_NOT_SET}\n"))
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "BF2_TEST_NOT_SET")
+	suite.assert.Contains(err.Error(), "azstorage.account-name")
+}
+
+func (suite *interpolateTestSuite) TestMissingFile() {
+	err := ReadConfigFromReader(strings.NewReader("azstorage:\n  account-key: \"@file:/nonexistent/key\"\n"))
+	suite.assert.NotNil(err)
+	suite.assert.Contains(err.Error(), "/nonexistent/key")
+}
+
+func (suite *interpolateTestSuite) TestReload() {
+	path := filepath.Join(suite.T().TempDir(), "config.yaml")
+	suite.assert.Nil(os.WriteFile(path, []byte("logging:\n  level: ${BF2_TEST_LEVEL}\n"), 0644))
+
+	suite.T().Setenv("BF2_TEST_LEVEL", "log_warning")
+	suite.assert.Nil(ReadFromConfigFile(path))
+
+	level := ""
+	suite.assert.Nil(UnmarshalKey("logging.level", &level))
+	suite.assert.Equal("log_warning", level)
+
+	suite.T().Setenv("BF2_TEST_LEVEL", "log_debug")
+	suite.assert.Nil(Reload())
+	suite.assert.Nil(UnmarshalKey("logging.level", &level))
+	suite.assert.Equal("log_debug", level)
+}
+
+func (suite *interpolateTestSuite) TestRestoreReferences() {
+	suite.T().Setenv("BF2_TEST_ACCOUNT", "myaccount")
+	suite.T().Setenv("BF2_TEST_CONTAINER", "mycontainer")
+
+	err := ReadConfigFromReader(strings.NewReader(`
+azstorage:
+  account-name: ${BF2_TEST_ACCOUNT}
+  container: ${BF2_TEST_CONTAINER}
+  tier: hot
+`))
+	suite.assert.Nil(err)
+
+	viper.Set("azstorage.container", "other")
+
+	settings := viper.AllSettings()
+	RestoreReferences(settings)
+
+	az := settings["azstorage"].(map[string]interface{})
+	suite.assert.Equal("${BF2_TEST_ACCOUNT}", az["account-name"])
+	suite.assert.Equal("other", az["container"])
+	suite.assert.Equal("hot", az["tier"])
+}
+
+func (suite *interpolateTestSuite) TestResolveReferences() {
+	suite.T().Setenv("BF2_TEST_TIMEOUT", "300")
+
+	conf, err := ResolveReferences(map[string]interface{}{
+		"file_cache": map[string]interface{}{"timeout-sec": "${BF2_TEST_TIMEOUT}", "path": "/tmp"},
+		"foreground": true,
+	})
+	suite.assert.Nil(err)
+	suite.assert.Equal(map[string]interface{}{"timeout-sec": "300", "path": "/tmp"}, conf["file_cache"])
+	suite.assert.Equal(true, conf["foreground"])
+}
@@ -38,6 +38,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const STRUCT_TAG = "config"
@@ -337,14 +338,36 @@ func isPrimitiveType(kind reflect.Kind) bool {
 func assignToField(field reflect.Value, val interface{}) {
 	if field.CanSet() {
 		if reflect.TypeOf(val).Kind() == reflect.String {
-			parseVal := parseValue(val.(string), field.Kind())
+			var parseVal interface{}
+			switch {
+			case field.Type() == reflect.TypeOf(time.Duration(0)):
+				if d, err := time.ParseDuration(val.(string)); err == nil {
+					parseVal = d
+				}
+			case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+				// Lists are given as comma separated values
+				parseVal = splitList(val.(string))
+			default:
+				parseVal = parseValue(val.(string), field.Kind())
+			}
 			if parseVal != nil {
-				field.Set(reflect.ValueOf(parseVal))
+				field.Set(reflect.ValueOf(parseVal).Convert(field.Type()))
 			}
-		} else {
-			field.Set(reflect.ValueOf(val))
+		} else if reflect.TypeOf(val).ConvertibleTo(field.Type()) {
+			field.Set(reflect.ValueOf(val).Convert(field.Type()))
+		}
+	}
+}
+
+// splitList : Items of a comma separated list, surrounding spaces trimmed
+func splitList(val string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
+	return items
 }
 
 // getIdxFromField is a utility function that returns the key to index into the map based on struct tags.
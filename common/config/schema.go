@@ -0,0 +1,796 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of problems reported while validating a config against the registered schemas
+const (
+	KindType       = "type"
+	KindRange      = "range"
+	KindExclusive  = "exclusive"
+	KindDeprecated = "deprecated"
+	KindUnknown    = "unknown"
+	KindUnused     = "unused"
+)
+
+// Severity of a problem, only errors make a config invalid
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationError : One problem found in a config, Key is the dot separated path of the offending key
+type ValidationError struct {
+	Key      string `json:"key"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+type ruleKind int
+
+const (
+	ruleRange ruleKind = iota
+	ruleOneOf
+	ruleExclusive
+	ruleDeprecated
+)
+
+// Rule : Check on the values of a section which the type of the keys alone does not express
+type Rule struct {
+	kind   ruleKind
+	keys   []string
+	min    float64
+	max    float64
+	values []string
+	hint   string
+}
+
+// Range : Numeric value of key has to be within [min, max]
+func Range(key string, min float64, max float64) Rule {
+	return Rule{kind: ruleRange, keys: []string{key}, min: min, max: max}
+}
+
+// AtLeast : Numeric value of key has to be min or more
+func AtLeast(key string, min float64) Rule {
+	return Range(key, min, math.Inf(1))
+}
+
+// OneOf : Value of key has to be one of the given values, compared ignoring case
+func OneOf(key string, values ...string) Rule {
+	return Rule{kind: ruleOneOf, keys: []string{key}, values: values}
+}
+
+// Exclusive : At most one of the keys can be set to a value other than false, zero or empty
+func Exclusive(keys ...string) Rule {
+	return Rule{kind: ruleExclusive, keys: keys}
+}
+
+// Deprecated : Key still works but should be replaced as the hint says
+func Deprecated(key string, hint string) Rule {
+	return Rule{kind: ruleDeprecated, keys: []string{key}, hint: hint}
+}
+
+type schema struct {
+	fields map[string]reflect.Type
+	rules  []Rule
+
+	// Keys of exported fields, the ones decoding can set
+	settable map[string]reflect.Type
+}
+
+var schemas = struct {
+	sync.RWMutex
+	sections map[string]*schema
+}{sections: make(map[string]*schema)}
+
+// RegisterSchema : Declare the keys a section of the config accepts, taken from the config tags of the fields of options.
+// The empty section stands for the top level of the config. Registering the same section again adds to it.
+func RegisterSchema(section string, options interface{}, rules ...Rule) {
+	schemas.Lock()
+	defer schemas.Unlock()
+
+	s, ok := schemas.sections[section]
+	if !ok {
+		s = &schema{fields: make(map[string]reflect.Type), settable: make(map[string]reflect.Type)}
+		schemas.sections[section] = s
+	}
+
+	if options != nil {
+		for key, t := range structFields(reflect.TypeOf(options)) {
+			s.fields[key] = t
+		}
+		for key, t := range exportedFields(reflect.TypeOf(options)) {
+			s.settable[key] = t
+		}
+	}
+	s.rules = append(s.rules, rules...)
+}
+
+// SchemaSections : Names of the sections having a schema, the top level excluded
+func SchemaSections() []string {
+	schemas.RLock()
+	defer schemas.RUnlock()
+
+	sections := make([]string, 0, len(schemas.sections))
+	for name := range schemas.sections {
+		if name != "" {
+			sections = append(sections, name)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// structFields : Keys of a struct type along with their types, fields without a config tag are not read from config
+func structFields(t reflect.Type) map[string]reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := make(map[string]reflect.Type)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get(STRUCT_TAG)
+		if key == "" || key == "-" {
+			continue
+		}
+		fields[strings.ToLower(key)] = t.Field(i).Type
+	}
+	return fields
+}
+
+// ValidateMap : Check a parsed config against the registered schemas.
+// Keys of unknown sections and keys not declared by their section are reported along with the closest known key.
+func ValidateMap(conf map[string]interface{}) []ValidationError {
+	schemas.RLock()
+	defer schemas.RUnlock()
+
+	conf = normalizeMap(conf)
+	errs := make([]ValidationError, 0)
+
+	top := schemas.sections[""]
+	if top == nil {
+		top = &schema{fields: make(map[string]reflect.Type)}
+	}
+
+	known := make([]string, 0, len(top.fields)+len(schemas.sections))
+	for key := range top.fields {
+		known = append(known, key)
+	}
+	for name := range schemas.sections {
+		if name != "" {
+			known = append(known, name)
+		}
+	}
+
+	for _, key := range sortedKeys(conf) {
+		if t, ok := top.fields[key]; ok {
+			errs = append(errs, checkValue(key, t, conf[key])...)
+			continue
+		}
+
+		section, ok := schemas.sections[key]
+		if !ok {
+			errs = append(errs, unknownKey(key, key, known))
+			continue
+		}
+
+		if conf[key] == nil {
+			continue
+		}
+
+		values, ok := conf[key].(map[string]interface{})
+		if !ok {
+			errs = append(errs, ValidationError{
+				Key:      key,
+				Kind:     KindType,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("expected a section, found %v", conf[key]),
+			})
+			continue
+		}
+
+		errs = append(errs, checkStruct(key, section.fields, values)...)
+		errs = append(errs, checkRules(key, section.rules, values)...)
+	}
+
+	errs = append(errs, checkRules("", top.rules, conf)...)
+	errs = append(errs, checkUnused(conf, top)...)
+	return errs
+}
+
+// checkUnused : Warn about sections of components which are not part of the pipeline, as they are ignored
+func checkUnused(conf map[string]interface{}, top *schema) []ValidationError {
+	list, ok := conf["components"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	inPipeline := make(map[string]bool)
+	for _, c := range list {
+		inPipeline[strings.ToLower(fmt.Sprint(c))] = true
+	}
+
+	errs := make([]ValidationError, 0)
+	for _, key := range sortedKeys(conf) {
+		_, isSection := schemas.sections[key]
+		_, isTopLevel := top.fields[key]
+		if isSection && !isTopLevel && !inPipeline[key] {
+			errs = append(errs, ValidationError{
+				Key:      key,
+				Kind:     KindUnused,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s is not in components, this section is ignored", key),
+			})
+		}
+	}
+	return errs
+}
+
+func checkStruct(prefix string, fields map[string]reflect.Type, values map[string]interface{}) []ValidationError {
+	errs := make([]ValidationError, 0)
+
+	known := make([]string, 0, len(fields))
+	for key := range fields {
+		known = append(known, key)
+	}
+
+	for _, key := range sortedKeys(values) {
+		t, ok := fields[key]
+		if !ok {
+			errs = append(errs, unknownKey(prefix+"."+key, key, known))
+			continue
+		}
+		errs = append(errs, checkValue(prefix+"."+key, t, values[key])...)
+	}
+	return errs
+}
+
+func typeError(key string, t reflect.Type, val interface{}) ValidationError {
+	return ValidationError{
+		Key:      key,
+		Kind:     KindType,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("expected %s, found %v", typeName(t), val),
+	}
+}
+
+// checkValue : Whether val can be decoded in to a field of type t, strings holding a value of the type are accepted
+func checkValue(key string, t reflect.Type, val interface{}) []ValidationError {
+	if val == nil {
+		return nil
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		switch v := val.(type) {
+		case int, int64, uint64, float64:
+			return nil
+		case string:
+			if _, err := time.ParseDuration(v); err == nil {
+				return nil
+			}
+		}
+		return []ValidationError{typeError(key, t, val)}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		switch v := val.(type) {
+		case bool:
+			return nil
+		case string:
+			if _, err := strconv.ParseBool(v); err == nil {
+				return nil
+			}
+		}
+		return []ValidationError{typeError(key, t, val)}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		num, ok := toNumber(val)
+		if !ok {
+			return []ValidationError{typeError(key, t, val)}
+		}
+		return checkNumber(key, t, num)
+
+	case reflect.String:
+		switch val.(type) {
+		case map[string]interface{}, []interface{}:
+			return []ValidationError{typeError(key, t, val)}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		list, ok := val.([]interface{})
+		if !ok {
+			// A single string is split on spaces in to a list of strings
+			if _, isString := val.(string); isString && t.Elem().Kind() == reflect.String {
+				return nil
+			}
+			return []ValidationError{typeError(key, t, val)}
+		}
+
+		errs := make([]ValidationError, 0)
+		for i, item := range list {
+			errs = append(errs, checkValue(fmt.Sprintf("%s[%d]", key, i), t.Elem(), item)...)
+		}
+		return errs
+
+	case reflect.Struct:
+		values, ok := val.(map[string]interface{})
+		if !ok {
+			return []ValidationError{typeError(key, t, val)}
+		}
+		return checkStruct(key, structFields(t), values)
+
+	case reflect.Map:
+		if _, ok := val.(map[string]interface{}); !ok {
+			return []ValidationError{typeError(key, t, val)}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// checkNumber : Numbers have to fit the type, no sign for unsigned and no fraction for integers
+func checkNumber(key string, t reflect.Type, num float64) []ValidationError {
+	var min, max float64
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min, max = 0, math.Pow(2, float64(t.Bits()))-1
+	default:
+		min, max = -math.Pow(2, float64(t.Bits()-1)), math.Pow(2, float64(t.Bits()-1))-1
+	}
+
+	if num != math.Trunc(num) {
+		return []ValidationError{{
+			Key:      key,
+			Kind:     KindType,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("expected %s, found %v", typeName(t), num),
+		}}
+	}
+
+	if num < min || num > max {
+		return []ValidationError{{
+			Key:      key,
+			Kind:     KindRange,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%v does not fit in %s, it shall be between %v and %v", num, typeName(t), min, max),
+		}}
+	}
+	return nil
+}
+
+func checkRules(prefix string, rules []Rule, values map[string]interface{}) []ValidationError {
+	errs := make([]ValidationError, 0)
+	path := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	for _, rule := range rules {
+		switch rule.kind {
+		case ruleRange:
+			val, ok := lookup(values, rule.keys[0])
+			if !ok {
+				continue
+			}
+			num, ok := toNumber(val)
+			if ok && (num < rule.min || num > rule.max) {
+				msg := fmt.Sprintf("%v is out of range, it shall be between %v and %v", val, rule.min, rule.max)
+				if math.IsInf(rule.max, 1) {
+					msg = fmt.Sprintf("%v is out of range, it shall be at least %v", val, rule.min)
+				}
+				errs = append(errs, ValidationError{Key: path(rule.keys[0]), Kind: KindRange, Severity: SeverityError, Message: msg})
+			}
+
+		case ruleOneOf:
+			val, ok := lookup(values, rule.keys[0])
+			if !ok || val == nil {
+				continue
+			}
+			found := false
+			for _, allowed := range rule.values {
+				if strings.EqualFold(fmt.Sprint(val), allowed) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				errs = append(errs, ValidationError{
+					Key:      path(rule.keys[0]),
+					Kind:     KindRange,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("%v is not supported, it shall be one of %s", val, strings.Join(rule.values, ", ")),
+				})
+			}
+
+		case ruleExclusive:
+			set := make([]string, 0)
+			for _, key := range rule.keys {
+				if val, ok := lookup(values, key); ok && !isZero(val) {
+					set = append(set, key)
+				}
+			}
+			if len(set) > 1 {
+				errs = append(errs, ValidationError{
+					Key:      path(set[0]),
+					Kind:     KindExclusive,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("%s can not be used together", strings.Join(set, " and ")),
+				})
+			}
+
+		case ruleDeprecated:
+			if _, ok := lookup(values, rule.keys[0]); ok {
+				errs = append(errs, ValidationError{
+					Key:      path(rule.keys[0]),
+					Kind:     KindDeprecated,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s is deprecated, %s", rule.keys[0], rule.hint),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// lookup : Value of a dot separated key in nested maps
+func lookup(values map[string]interface{}, key string) (interface{}, bool) {
+	parts := strings.Split(strings.ToLower(key), ".")
+	cur := values
+	for i, part := range parts {
+		val, ok := cur[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return val, true
+		}
+		cur, ok = val.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func isZero(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return !b
+		}
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+
+	if num, ok := toNumber(val); ok {
+		return num == 0
+	}
+	return false
+}
+
+func toNumber(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		if i, err := strconv.ParseInt(v, 0, 64); err == nil {
+			return float64(i), true
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+func typeName(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "a duration"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	default:
+		return "a section"
+	}
+}
+
+// unknownKey : Report a key nothing reads, suggesting the known key closest to it
+func unknownKey(path string, key string, known []string) ValidationError {
+	msg := fmt.Sprintf("unknown key %s, it is ignored", key)
+
+	best, bestDist := "", -1
+	for _, k := range known {
+		d := editDistance(key, k)
+		if bestDist < 0 || d < bestDist || (d == bestDist && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	if bestDist >= 0 && bestDist <= len(key)/3+1 {
+		msg += fmt.Sprintf(", did you mean %s?", best)
+	}
+
+	return ValidationError{Key: path, Kind: KindUnknown, Severity: SeverityError, Message: msg}
+}
+
+// editDistance : Levenshtein distance between two keys
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// normalizeMap : Lower case keys, as viper does, and turn the maps decoded by yaml.v2 in to string keyed maps
+func normalizeMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[strings.ToLower(k)] = normalizeValue(v)
+	}
+	return out
+}
+
+func normalizeValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return normalizeMap(v)
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = item
+		}
+		return normalizeMap(m)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = normalizeValue(item)
+		}
+		return list
+	case int32:
+		return int64(v)
+	case uint:
+		return uint64(v)
+	case uint32:
+		return uint64(v)
+	case float32:
+		return float64(v)
+	}
+	return val
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// JSONSchema : Draft-07 JSON schema of the config built from the registered schemas, for editors and linters
+func JSONSchema() map[string]interface{} {
+	schemas.RLock()
+	defer schemas.RUnlock()
+
+	root := map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "blobfuse2 config",
+		"type":                 "object",
+		"additionalProperties": false,
+	}
+
+	props := make(map[string]interface{})
+	if top, ok := schemas.sections[""]; ok {
+		for key, t := range top.fields {
+			props[key] = jsonType(t)
+		}
+		applyRules(props, top.rules)
+	}
+
+	for name, s := range schemas.sections {
+		if name == "" {
+			continue
+		}
+		sectionProps := make(map[string]interface{})
+		for key, t := range s.fields {
+			sectionProps[key] = jsonType(t)
+		}
+		applyRules(sectionProps, s.rules)
+		props[name] = map[string]interface{}{
+			"type":                 "object",
+			"properties":           sectionProps,
+			"additionalProperties": false,
+		}
+	}
+
+	root["properties"] = props
+	return root
+}
+
+// applyRules : Carry the ranges, allowed values and deprecations over to the properties they apply to
+func applyRules(props map[string]interface{}, rules []Rule) {
+	for _, rule := range rules {
+		prop := jsonProperty(props, rule.keys[0])
+		if prop == nil {
+			continue
+		}
+
+		switch rule.kind {
+		case ruleRange:
+			prop["minimum"] = rule.min
+			if !math.IsInf(rule.max, 1) {
+				prop["maximum"] = rule.max
+			}
+		case ruleOneOf:
+			prop["enum"] = rule.values
+		case ruleDeprecated:
+			prop["deprecated"] = true
+			prop["description"] = rule.hint
+		}
+	}
+}
+
+// jsonProperty : Property of a dot separated key, looking through the properties of nested objects
+func jsonProperty(props map[string]interface{}, key string) map[string]interface{} {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		prop, ok := props[part].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if i == len(parts)-1 {
+			return prop
+		}
+		props, ok = prop["properties"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
+func jsonType(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]interface{}{"type": []string{"string", "integer"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": jsonType(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		for key, ft := range structFields(t) {
+			props[key] = jsonType(ft)
+		}
+		return map[string]interface{}{"type": "object", "properties": props, "additionalProperties": false}
+	default:
+		return map[string]interface{}{"type": "object"}
+	}
+}
+
+// resetSchemas : Forget all registered schemas, for tests
+func resetSchemas() {
+	schemas.Lock()
+	defer schemas.Unlock()
+	schemas.sections = make(map[string]*schema)
+}
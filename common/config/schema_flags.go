@@ -0,0 +1,221 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Prefix of the environment variables generated for config keys, e.g. BLOBFUSE2_FILE_CACHE_TIMEOUT_SEC
+const schemaEnvPrefix = "BLOBFUSE2_"
+
+// AddSchemaFlags : Give every key of the registered component sections a flag named <section>.<key> and an environment
+// variable, so a mount can be configured without a config file. A key already bound to a flag gets the new flag as an
+// alias of it, a key already read from an environment variable keeps that one. Deprecated keys and lists of sections get none.
+func AddSchemaFlags() {
+	schemas.RLock()
+	defer schemas.RUnlock()
+
+	for _, section := range sortedSections() {
+		s := schemas.sections[section]
+
+		deprecated := make(map[string]bool)
+		for _, rule := range s.rules {
+			if rule.kind == ruleDeprecated {
+				deprecated[rule.keys[0]] = true
+			}
+		}
+
+		for _, key := range schemaKeys(section, s.settable, deprecated) {
+			if !isBound(userOptions.envTree, key) {
+				BindEnv(key, SchemaEnvName(key))
+			}
+			envName := userOptions.envTree.GetSubTree(key).value.(string)
+
+			if userOptions.flags.Lookup(key) != nil {
+				continue
+			}
+
+			if isBound(userOptions.flagTree, key) {
+				bound := userOptions.flagTree.GetSubTree(key).value.(*pflag.Flag)
+				addFlagAlias(key, bound, envName)
+				continue
+			}
+
+			flag := addSchemaFlag(key, fieldType(s.settable, strings.TrimPrefix(key, section+".")), envName)
+			if flag != nil {
+				BindPFlag(key, flag)
+			}
+		}
+	}
+}
+
+// SchemaEnvName : Environment variable generated for a config key
+func SchemaEnvName(key string) string {
+	return schemaEnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+func sortedSections() []string {
+	sections := make([]string, 0, len(schemas.sections))
+	for name := range schemas.sections {
+		if name != "" {
+			sections = append(sections, name)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// schemaKeys : Dot separated keys of the values a flag can hold, nested sections are walked
+func schemaKeys(prefix string, fields map[string]reflect.Type, skip map[string]bool) []string {
+	keys := make([]string, 0, len(fields))
+	for name, t := range fields {
+		if skip[name] {
+			continue
+		}
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		key := prefix + "." + name
+		switch {
+		case t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Duration(0)):
+			keys = append(keys, schemaKeys(key, exportedFields(t), nil)...)
+		case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.String:
+		case t.Kind() == reflect.Map:
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exportedFields : Keys of a struct type which decoding can set
+func exportedFields(t reflect.Type) map[string]reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := structFields(t)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			delete(fields, strings.ToLower(t.Field(i).Tag.Get(STRUCT_TAG)))
+		}
+	}
+	return fields
+}
+
+// fieldType : Type of a dot separated key within fields
+func fieldType(fields map[string]reflect.Type, key string) reflect.Type {
+	parts := strings.Split(key, ".")
+	t := fields[parts[0]]
+	for _, part := range parts[1:] {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		t = structFields(t)[part]
+	}
+	return t
+}
+
+func isBound(tree *Tree, key string) bool {
+	node := tree.GetSubTree(key)
+	return node != nil && node.value != nil
+}
+
+// addFlagAlias : Flag sharing the value of the flag bound to the key, see flagChanged
+func addFlagAlias(name string, bound *pflag.Flag, envName string) {
+	alias := &pflag.Flag{
+		Name:     name,
+		Usage:    fmt.Sprintf("Sets %s of the config, same as --%s. Environment variable %s.", name, bound.Name, envName),
+		Value:    bound.Value,
+		DefValue: bound.DefValue,
+	}
+	if bound.NoOptDefVal != "" {
+		alias.NoOptDefVal = bound.NoOptDefVal
+	}
+
+	userOptions.flags.AddFlag(alias)
+	userOptions.aliases[bound] = append(userOptions.aliases[bound], alias)
+}
+
+// flagChanged : Whether the flag or one of its aliases was given
+func flagChanged(flag *pflag.Flag) bool {
+	if flag.Changed {
+		return true
+	}
+	for _, alias := range userOptions.aliases[flag] {
+		if alias.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// addSchemaFlag : Flag of the type of the key, values are applied only when the flag is given
+func addSchemaFlag(key string, t reflect.Type, envName string) *pflag.Flag {
+	usage := fmt.Sprintf("Sets %s of the config. Environment variable %s.", key, envName)
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return AddDurationFlag(key, 0, usage)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return AddBoolFlag(key, false, usage)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return AddInt64Flag(key, 0, usage)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return AddUint64Flag(key, 0, usage)
+	case reflect.Float32, reflect.Float64:
+		return AddFloat64Flag(key, 0, usage)
+	case reflect.String:
+		return AddStringFlag(key, "", usage)
+	case reflect.Slice:
+		return AddStringSliceFlag(key, []string{}, usage)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type schemaFlagsTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+type schemaFlagsNested struct {
+	Level string `config:"level"`
+}
+
+type schemaFlagsOptions struct {
+	Path     string            `config:"path"`
+	Timeout  uint32            `config:"timeout-sec"`
+	Policy   string            `config:"policy"`
+	Account  string            `config:"account-name"`
+	Ratio    float64           `config:"ratio"`
+	Enabled  bool              `config:"enabled"`
+	Pin      []string          `config:"pin"`
+	Wait     time.Duration     `config:"wait"`
+	Nested   schemaFlagsNested `config:"nested"`
+	Rules    []schemaRule      `config:"rules"`
+	OldKey   uint32            `config:"old-key"`
+	internal bool              `config:"internal"`
+}
+
+func (suite *schemaFlagsTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	ResetConfig()
+	resetSchemas()
+
+	RegisterSchema("comp", schemaFlagsOptions{}, Deprecated("old-key", "use timeout-sec instead"))
+
+	policy := AddStringFlag("comp-policy", "lru", "legacy flag")
+	BindPFlag("comp.policy", policy)
+	BindEnv("comp.account-name", "COMP_ACCOUNT")
+
+	AddSchemaFlags()
+}
+
+func (suite *schemaFlagsTestSuite) TearDownTest() {
+	ResetConfig()
+	resetSchemas()
+}
+
+func TestSchemaFlags(t *testing.T) {
+	suite.Run(t, new(schemaFlagsTestSuite))
+}
+
+func (suite *schemaFlagsTestSuite) parse(args ...string) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AttachToFlagSet(fs)
+	suite.assert.Nil(fs.Parse(args))
+}
+
+func (suite *schemaFlagsTestSuite) TestFlagsGenerated() {
+	for _, name := range []string{"comp.path", "comp.timeout-sec", "comp.policy", "comp.ratio", "comp.enabled", "comp.pin", "comp.wait", "comp.nested.level"} {
+		suite.assert.NotNil(userOptions.flags.Lookup(name), name)
+	}
+
+	for _, name := range []string{"comp.rules", "comp.old-key", "comp.internal"} {
+		suite.assert.Nil(userOptions.flags.Lookup(name), name)
+	}
+
+	suite.assert.Contains(userOptions.flags.Lookup("comp.timeout-sec").Usage, "BLOBFUSE2_COMP_TIMEOUT_SEC")
+	suite.assert.Contains(userOptions.flags.Lookup("comp.account-name").Usage, "COMP_ACCOUNT")
+	suite.assert.Contains(userOptions.flags.Lookup("comp.policy").Usage, "--comp-policy")
+}
+
+func (suite *schemaFlagsTestSuite) TestFlags() {
+	suite.parse("--comp.path=/tmp/cache", "--comp.timeout-sec=300", "--comp.ratio=0.5", "--comp.enabled",
+		"--comp.pin=a/**,b/**", "--comp.wait=5s", "--comp.nested.level=debug")
+
+	opts := schemaFlagsOptions{}
+	suite.assert.Nil(UnmarshalKey("comp", &opts))
+	suite.assert.Equal("/tmp/cache", opts.Path)
+	suite.assert.EqualValues(300, opts.Timeout)
+	suite.assert.Equal(0.5, opts.Ratio)
+	suite.assert.True(opts.Enabled)
+	suite.assert.Equal([]string{"a/**", "b/**"}, opts.Pin)
+	suite.assert.Equal(5*time.Second, opts.Wait)
+	suite.assert.Equal("debug", opts.Nested.Level)
+	suite.assert.True(IsSet("comp.timeout-sec"))
+	suite.assert.False(IsSet("comp.policy"))
+}
+
+func (suite *schemaFlagsTestSuite) TestAlias() {
+	suite.parse("--comp.policy=lfu")
+
+	opts := schemaFlagsOptions{}
+	suite.assert.Nil(UnmarshalKey("comp", &opts))
+	suite.assert.Equal("lfu", opts.Policy)
+	suite.assert.True(IsSet("comp.policy"))
+}
+
+func (suite *schemaFlagsTestSuite) TestEnv() {
+	suite.T().Setenv("BLOBFUSE2_COMP_TIMEOUT_SEC", "60")
+	suite.T().Setenv("BLOBFUSE2_COMP_PIN", "logs/**, data/**")
+	suite.T().Setenv("COMP_ACCOUNT", "myaccount")
+	suite.parse()
+
+	opts := schemaFlagsOptions{}
+	suite.assert.Nil(UnmarshalKey("comp", &opts))
+	suite.assert.EqualValues(60, opts.Timeout)
+	suite.assert.Equal([]string{"logs/**", "data/**"}, opts.Pin)
+	suite.assert.Equal("myaccount", opts.Account)
+	suite.assert.True(IsSet("comp.timeout-sec"))
+	suite.assert.False(IsSet("comp.path"))
+	suite.assert.False(IsSet("comp"))
+}
+
+func (suite *schemaFlagsTestSuite) TestFlagOverEnv() {
+	suite.T().Setenv("BLOBFUSE2_COMP_TIMEOUT_SEC", "60")
+	suite.parse("--comp.timeout-sec=300")
+
+	opts := schemaFlagsOptions{}
+	suite.assert.Nil(UnmarshalKey("comp", &opts))
+	suite.assert.EqualValues(300, opts.Timeout)
+}
+
+func (suite *schemaFlagsTestSuite) TestSchemaEnvName() {
+	suite.assert.Equal("BLOBFUSE2_FILE_CACHE_TIMEOUT_SEC", SchemaEnvName("file_cache.timeout-sec"))
+	suite.assert.Equal("BLOBFUSE2_LIBFUSE_NESTED_LEVEL", SchemaEnvName("libfuse.nested.level"))
+}
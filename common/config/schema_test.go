@@ -0,0 +1,218 @@
+/*
+    _____           _____   _____   ____          ______  _____  ------
+   |     |  |      |     | |     | |     |     | |       |            |
+   |     |  |      |     | |     | |     |     | |       |            |
+   | --- |  |      |     | |-----| |---- |     | |-----| |-----  ------
+   |     |  |      |     | |     | |     |     |       | |       |
+   | ____|  |_____ | ____| | ____| |     |_____|  _____| |_____  |_____
+
+
+   Licensed under the MIT License <http://opensource.org/licenses/MIT>.
+
+   Copyright © 2020-2023 Microsoft Corporation. All rights reserved.
+   Author : <blobfusedev@microsoft.com>
+
+   Permission is hereby granted, free of charge, to any person obtaining a copy
+   of this software and associated documentation files (the "Software"), to deal
+   in the Software without restriction, including without limitation the rights
+   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+   copies of the Software, and to permit persons to whom the Software is
+   furnished to do so, subject to the following conditions:
+
+   The above copyright notice and this permission notice shall be included in all
+   copies or substantial portions of the Software.
+
+   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+   SOFTWARE
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type schemaTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+type schemaRule struct {
+	Pattern string `config:"pattern"`
+	Timeout uint32 `config:"timeout-sec"`
+}
+
+type schemaComponentOptions struct {
+	Path      string        `config:"path"`
+	Timeout   uint32        `config:"timeout-sec"`
+	Policy    string        `config:"policy"`
+	Watermark uint32        `config:"high-watermark"`
+	WriteBack bool          `config:"write-back"`
+	Shared    bool          `config:"shared"`
+	Rules     []schemaRule  `config:"rules"`
+	Wait      time.Duration `config:"wait"`
+	OldKey    uint32        `config:"old-key"`
+	internal  bool          `config:"internal"`
+	Untagged  string
+}
+
+type schemaTopOptions struct {
+	Components []string `config:"components"`
+	Foreground bool     `config:"foreground"`
+	Logging    struct {
+		Level string `config:"level"`
+	} `config:"logging"`
+}
+
+func (suite *schemaTestSuite) SetupTest() {
+	suite.assert = assert.New(suite.T())
+	resetSchemas()
+
+	RegisterSchema("", schemaTopOptions{}, OneOf("logging.level", "log_debug", "log_err"))
+	RegisterSchema("comp", schemaComponentOptions{},
+		OneOf("policy", "lru", "lfu"),
+		Range("high-watermark", 0, 100),
+		Exclusive("write-back", "shared"),
+		Deprecated("old-key", "use timeout-sec instead"))
+}
+
+func (suite *schemaTestSuite) TearDownTest() {
+	resetSchemas()
+}
+
+func TestSchema(t *testing.T) {
+	suite.Run(t, new(schemaTestSuite))
+}
+
+func (suite *schemaTestSuite) validate(conf string) []ValidationError {
+	values := make(map[string]interface{})
+	suite.assert.NoError(yaml.Unmarshal([]byte(conf), &values))
+	return ValidateMap(values)
+}
+
+func (suite *schemaTestSuite) TestValidConfig() {
+	errs := suite.validate(`
+components:
+  - comp
+foreground: true
+logging:
+  level: LOG_DEBUG
+comp:
+  path: /tmp
+  timeout-sec: "120"
+  policy: lfu
+  high-watermark: 90
+  write-back: true
+  shared: false
+  wait: 5s
+  internal: true
+  rules:
+    - pattern: "*.log"
+      timeout-sec: 10
+`)
+	suite.assert.Empty(errs)
+}
+
+func (suite *schemaTestSuite) TestUnknownKeys() {
+	errs := suite.validate(`
+comp:
+  timeout-secs: 10
+  untagged: abc
+compp:
+  path: /tmp
+`)
+	suite.assert.Len(errs, 3)
+	suite.assert.Equal("comp.timeout-secs", errs[0].Key)
+	suite.assert.Equal(KindUnknown, errs[0].Kind)
+	suite.assert.Contains(errs[0].Message, "did you mean timeout-sec?")
+	suite.assert.Equal("comp.untagged", errs[1].Key)
+	suite.assert.Equal("compp", errs[2].Key)
+	suite.assert.Contains(errs[2].Message, "did you mean comp?")
+}
+
+func (suite *schemaTestSuite) TestTypes() {
+	errs := suite.validate(`
+foreground: maybe
+comp:
+  timeout-sec: -1
+  high-watermark: 1.5
+  wait: soon
+  rules:
+    - pattern: "*.log"
+      timeout-sec: ten
+  path:
+    nested: true
+`)
+	keys := make([]string, 0)
+	for _, e := range errs {
+		keys = append(keys, e.Key)
+		suite.assert.Equal(SeverityError, e.Severity)
+	}
+	suite.assert.ElementsMatch([]string{"foreground", "comp.timeout-sec", "comp.high-watermark", "comp.wait", "comp.rules[0].timeout-sec", "comp.path"}, keys)
+}
+
+func (suite *schemaTestSuite) TestRules() {
+	errs := suite.validate(`
+logging:
+  level: verbose
+comp:
+  policy: fifo
+  high-watermark: 120
+  write-back: true
+  shared: "true"
+  old-key: 10
+`)
+	suite.assert.Len(errs, 5)
+
+	byKey := make(map[string]ValidationError)
+	for _, e := range errs {
+		byKey[e.Key] = e
+	}
+	suite.assert.Equal(KindRange, byKey["logging.level"].Kind)
+	suite.assert.Equal(KindRange, byKey["comp.policy"].Kind)
+	suite.assert.Equal(KindRange, byKey["comp.high-watermark"].Kind)
+	suite.assert.Equal(KindExclusive, byKey["comp.write-back"].Kind)
+	suite.assert.Equal(KindDeprecated, byKey["comp.old-key"].Kind)
+	suite.assert.Equal(SeverityWarning, byKey["comp.old-key"].Severity)
+}
+
+func (suite *schemaTestSuite) TestUnusedSection() {
+	errs := suite.validate(`
+components:
+  - other
+comp:
+  path: /tmp
+`)
+	suite.assert.Len(errs, 1)
+	suite.assert.Equal(KindUnused, errs[0].Kind)
+	suite.assert.Equal(SeverityWarning, errs[0].Severity)
+}
+
+func (suite *schemaTestSuite) TestJSONSchema() {
+	s := JSONSchema()
+	suite.assert.Equal("object", s["type"])
+
+	props := s["properties"].(map[string]interface{})
+	suite.assert.Contains(props, "foreground")
+	suite.assert.Contains(props, "comp")
+
+	comp := props["comp"].(map[string]interface{})["properties"].(map[string]interface{})
+	suite.assert.Equal([]string{"lru", "lfu"}, comp["policy"].(map[string]interface{})["enum"])
+	suite.assert.Equal(float64(100), comp["high-watermark"].(map[string]interface{})["maximum"])
+	suite.assert.Equal(true, comp["old-key"].(map[string]interface{})["deprecated"])
+	suite.assert.Equal("array", comp["rules"].(map[string]interface{})["type"])
+	suite.assert.NotContains(comp, "untagged")
+
+	logging := props["logging"].(map[string]interface{})["properties"].(map[string]interface{})
+	suite.assert.Equal([]string{"log_debug", "log_err"}, logging["level"].(map[string]interface{})["enum"])
+}
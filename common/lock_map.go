@@ -87,6 +87,15 @@ func (l *LockMapItem) Lock() {
 	l.exLocked = true
 }
 
+// Lock this file exclusively if no one else holds it, without waiting
+func (l *LockMapItem) TryLock() bool {
+	if !l.mtx.TryLock() {
+		return false
+	}
+	l.exLocked = true
+	return true
+}
+
 // UnLock this file exclusively
 func (l *LockMapItem) Unlock() {
 	l.exLocked = false
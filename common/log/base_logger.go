@@ -34,6 +34,7 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -53,6 +54,7 @@ type LogFileConfig struct {
 	LogFileCount int
 	LogLevel     common.LogLevel
 	LogTag       string
+	LogFormat    string // text, the default, or json for one object per line
 
 	currentLogSize uint64
 }
@@ -219,6 +221,11 @@ func (l *BaseLogger) logEvent(lvl string, format string, args ...interface{}) {
 	// Only log if the log level matches the log request
 	_, fn, ln, _ := runtime.Caller(3)
 	msg := fmt.Sprintf(format, args...)
+	if l.fileConfig.LogFormat == "json" {
+		l.channel <- l.jsonEvent(lvl, fmt.Sprintf("%s:%d", filepath.Base(fn), ln), msg)
+		return
+	}
+
 	msg = fmt.Sprintf("%s : %s[%d] : %s [%s (%d)]: %s",
 		time.Now().Format(time.UnixDate),
 		l.fileConfig.LogTag,
@@ -230,6 +237,22 @@ func (l *BaseLogger) logEvent(lvl string, format string, args ...interface{}) {
 	l.channel <- msg
 }
 
+// jsonEvent : Log as a single line JSON object, for collectors reading the output of containers
+func (l *BaseLogger) jsonEvent(lvl string, source string, msg string) string {
+	event, err := json.Marshal(struct {
+		Time   string `json:"time"`
+		Level  string `json:"level"`
+		Tag    string `json:"tag"`
+		PID    int    `json:"pid"`
+		Source string `json:"source"`
+		Msg    string `json:"msg"`
+	}{time.Now().Format(time.RFC3339Nano), lvl, l.fileConfig.LogTag, l.procPID, source, msg})
+	if err != nil {
+		return msg
+	}
+	return string(event)
+}
+
 // logDumper : logEvent just enqueues an event in the channel, this thread dumps that log to the file
 func (l *BaseLogger) logDumper(id int, channel <-chan string) {
 	defer l.workerDone.Done()
@@ -247,13 +270,14 @@ func (l *BaseLogger) logDumper(id int, channel <-chan string) {
 
 func (l *BaseLogger) LogRotate() error {
 	//fmt.Println("Log Rotation started")
-	if err := l.logFileHandle.Close(); err != nil {
-		return err
-	}
-	// skip if the file is standard output
+	// skip if the file is standard output, which is kept open
 	if l.fileConfig.LogFile == "stdout" {
+		l.fileConfig.currentLogSize = 0
 		return nil
 	}
+	if err := l.logFileHandle.Close(); err != nil {
+		return err
+	}
 
 	var fname string
 	var fnameNew string